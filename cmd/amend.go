@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+var amendForce = false
+
+// amendCmd represents the amend command
+var amendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Regenerate the message of the last commit",
+	Long: `Generate a new message for the last commit from its changes plus anything
+staged since, then amend it. The author and the existing trailers are kept.
+A commit that is already on a remote branch is only amended with --force.
+
+Example:
+  gmc amend
+  gmc amend -a -c "retry the upload on timeouts"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// The AI picks files from the worktree, which has no place in a commit
+		// that already exists
+		amend, autoSelect, push = true, false, false
+		RootCmd.Run(cmd, args)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(amendCmd)
+
+	amendCmd.Flags().
+		BoolVar(&amendForce, "force", amendForce, "amend even if the last commit was already pushed")
+	amendCmd.Flags().
+		BoolVarP(&stageAll, "all", "a", stageAll, "stage all changes in tracked files into the amended commit")
+	amendCmd.Flags().
+		BoolVarP(&noConfirm, "yes", "y", noConfirm, "skip confirmation prompt")
+	amendCmd.Flags().
+		BoolVarP(&quiet, "quiet", "q", quiet, "suppress output (only works with --yes)")
+	amendCmd.Flags().
+		StringVarP(&userContext, "context", "c", "", "additional context to be added to the commit message")
+	amendCmd.Flags().
+		StringVarP(&model, "model", "m", service.DefaultModel, "google gemini model to use")
+	amendCmd.Flags().
+		BoolVar(&dryRun, "dry-run", dryRun, "run the command without making any changes")
+	amendCmd.Flags().
+		BoolVar(&showDiff, "show-diff", showDiff, "show the diff before committing")
+	amendCmd.Flags().
+		StringVar(&commitType, "type", "", "commit type to use, e.g. feat or fix; Gemini only writes the description")
+	amendCmd.Flags().
+		StringVar(&commitScope, "scope", "", "commit scope to use")
+	amendCmd.Flags().
+		BoolVar(&breaking, "breaking", breaking, "mark the commit as a breaking change and require a BREAKING CHANGE footer")
+	amendCmd.Flags().
+		BoolVar(&noVerify, "no-verify", noVerify, "skip git commit-msg hook verification")
+}
@@ -23,9 +23,14 @@ var getCmd = &cobra.Command{
   api.baseurl         - Custom base URL for Gemini API
 
 [commit]
-  commit.language     - Language for commit messages
-  commit.max_length   - Maximum length of commit message
-  commit.issue_footer - Keyword for auto-appended issue trailer
+  commit.language           - Language for commit messages
+  commit.max_length         - Deprecated alias of commit.subject_max_length
+  commit.subject_max_length - Maximum length of the subject line
+  commit.body_wrap          - Column to hard-wrap the body at
+  commit.issue_footer       - Keyword for auto-appended issue trailer
+  commit.signoff            - Add a Signed-off-by trailer
+  commit.trailers           - Extra trailers
+  commit.enforce_branch_type - Require the commit type implied by the branch name
 
 [behavior]
   behavior.stage_all   - Stage all changes in tracked files
@@ -36,6 +41,34 @@ var getCmd = &cobra.Command{
   behavior.dry_run     - Run without making changes
   behavior.show_diff   - Show diff before committing
   behavior.no_verify   - Skip git commit-msg hook verification
+  behavior.privacy     - How much of the change to send
+
+[diff]
+  diff.exclude         - Patterns whose diff is replaced by a stub
+  diff.max_file_kb     - Size above which new files are summarised
+  diff.algorithm       - Diff algorithm
+  diff.context_lines   - Unchanged lines shown around each change
+  diff.function_context - Show the whole function around each change
+  diff.ignore_whitespace - Ignore whitespace-only changes
+  diff.renames         - Rename detection
+  diff.full_files_kb   - Budget for sending small modified files in full
+
+[issue]
+  issue.patterns       - Regexes for issues in branch names
+  issue.tracker        - Issue tracker
+  issue.url            - Tracker base URL
+  issue.placement      - Where the issue goes
+  issue.fetch          - Add the issue's title and description to the prompt
+  issue.repo           - Repository to fetch GitHub or GitLab issues from
+  issue.token          - API token for the tracker
+  issue.api_url        - Tracker API URL
+
+[context]
+  context.neighbors    - Neighboring files to list
+
+[secrets]
+  secrets.action       - What to do when the diff contains secrets
+  secrets.patterns     - Extra regexes to treat as secrets
 
 Example:
   gmc config get commit.language
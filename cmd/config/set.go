@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -14,10 +15,48 @@ import (
 var ValidConfigKeys = map[string]bool{
 	"api.key": true, "api.model": true, "api.baseurl": true,
 	"commit.language": true, "commit.max_length": true, "commit.issue_footer": true,
+	"commit.subject_max_length": true, "commit.body_wrap": true,
+	"commit.signoff": true, "commit.trailers": true, "commit.enforce_branch_type": true,
 	"behavior.stage_all": true, "behavior.auto_select": true,
 	"behavior.no_confirm": true, "behavior.quiet": true,
 	"behavior.push": true, "behavior.dry_run": true,
-	"behavior.show_diff": true, "behavior.no_verify": true,
+	"behavior.show_diff": true, "behavior.no_verify": true, "behavior.privacy": true,
+	"diff.exclude": true, "diff.max_file_kb": true, "diff.algorithm": true,
+	"diff.context_lines": true, "diff.function_context": true,
+	"diff.ignore_whitespace": true, "diff.renames": true, "diff.full_files_kb": true,
+	"issue.patterns": true, "issue.tracker": true, "issue.url": true, "issue.placement": true,
+	"issue.fetch": true, "issue.repo": true, "issue.token": true, "issue.api_url": true,
+	"context.neighbors": true, "secrets.action": true, "secrets.patterns": true,
+}
+
+// ListConfigKeys hold lists whose entries may contain spaces, like trailers and
+// regexes. set stores them as a TOML array, splitting the value on newlines and ";".
+var ListConfigKeys = map[string]bool{
+	"commit.trailers": true, "issue.patterns": true, "secrets.patterns": true,
+}
+
+// SplitList splits a list value given on the command line into its entries
+func SplitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// GetList reads one of ListConfigKeys. A plain string, as older versions of set
+// saved, is split like a value given to set instead of on whitespace.
+func GetList(key string) []string {
+	switch value := viper.Get(key).(type) {
+	case nil:
+		return nil
+	case string:
+		return SplitList(value)
+	default:
+		return viper.GetStringSlice(key)
+	}
 }
 
 var setCmd = &cobra.Command{
@@ -31,9 +70,14 @@ var setCmd = &cobra.Command{
   api.baseurl         - Custom base URL for Gemini API
 
 [commit]
-  commit.language     - Language for commit messages (default: english)
-  commit.max_length   - Maximum length of commit message (default: 72)
-  commit.issue_footer - Keyword for auto-appended issue trailer, e.g. Refs/Closes/Fixes (default: Refs)
+  commit.language           - Language for commit messages (default: english)
+  commit.max_length         - Deprecated alias of commit.subject_max_length
+  commit.subject_max_length - Maximum length of the subject line (default: 72)
+  commit.body_wrap          - Column to hard-wrap the body at, 0 to disable (default: 72)
+  commit.issue_footer       - Keyword for auto-appended issue trailer, e.g. Refs/Closes/Fixes (default: Refs)
+  commit.signoff            - Add a Signed-off-by trailer (default: false)
+  commit.trailers           - Extra trailers, separated by ";"
+  commit.enforce_branch_type - Require the commit type implied by the branch name (default: false)
 
 [behavior]
   behavior.stage_all   - Stage all changes in tracked files (default: false)
@@ -44,11 +88,40 @@ var setCmd = &cobra.Command{
   behavior.dry_run     - Run without making changes (default: false)
   behavior.show_diff   - Show diff before committing (default: false)
   behavior.no_verify   - Skip git commit-msg hook verification (default: false)
+  behavior.privacy     - How much of the change to send: full, symbols or stats (default: full)
+
+[diff]
+  diff.exclude         - Space-separated gitignore-style patterns whose diff is replaced by a stub
+  diff.max_file_kb     - Size above which new files are summarised, -1 to disable (default: 256)
+  diff.algorithm       - Diff algorithm: myers, minimal, patience or histogram (default: minimal)
+  diff.context_lines   - Unchanged lines shown around each change (default: 3)
+  diff.function_context - Show the whole function around each change (default: false)
+  diff.ignore_whitespace - Ignore whitespace-only changes (default: false)
+  diff.renames         - Rename detection: renames, copies or off (default: renames)
+  diff.full_files_kb   - Budget for sending small modified files in full, 0 to disable (default: 0)
+
+[issue]
+  issue.patterns       - Regexes for issues in branch names, with a "key" or "number" group, separated by ";"
+  issue.tracker        - Issue tracker: github, gitlab, jira or linear
+  issue.url            - Tracker base URL; when set the footer links to the issue
+  issue.placement      - Where the issue goes: footer, prefix or suffix (default: footer)
+  issue.fetch          - Add the issue's title and description to the prompt (default: false)
+  issue.repo           - GitHub or GitLab repository to fetch issues from, owner/repo or a URL (default: the origin remote)
+  issue.token          - API token for the tracker; "email:api-token" for Jira Cloud
+  issue.api_url        - Tracker API URL, e.g. for GitHub Enterprise
+
+[context]
+  context.neighbors    - Neighboring files to list: off, tracked or all (default: tracked)
+
+[secrets]
+  secrets.action       - What to do when the diff contains secrets: warn, abort or off (default: warn)
+  secrets.patterns     - Extra regexes to treat as secrets, separated by ";"
 
 Example:
   gmc config set commit.language korean
   gmc config set commit.max_length 100
-  gmc config set behavior.push true`,
+  gmc config set behavior.push true
+  gmc config set commit.trailers "Reviewed-by: Jane <jane@example.com>; Team: core"`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
@@ -60,7 +133,11 @@ Example:
 			os.Exit(1)
 		}
 
-		viper.Set(key, value)
+		if ListConfigKeys[key] {
+			viper.Set(key, SplitList(value))
+		} else {
+			viper.Set(key, value)
+		}
 		if err := viper.WriteConfig(); err != nil {
 			fmt.Printf("Error: failed to write config: %v\n", err)
 			os.Exit(1)
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/tfkhdyt/geminicommit/internal/delivery/cli/handler"
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+var (
+	hookHandler   = handler.NewHookHandler()
+	hookName      = service.PrepareCommitMsgHook
+	hookCommitMsg = false
+	hookFix       = false
+)
+
+// hookCmd represents the hook command
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Generate and check commit messages from plain git commit and IDEs",
+	Long: `Install a prepare-commit-msg git hook that writes the generated message into
+the commit message file, so it shows up in the editor of a plain ` + "`git commit`" + `
+or an IDE. With --commit-msg, install a commit-msg hook instead that lints the
+message the author wrote and fails the commit when it breaks the rules.
+Hooks honour core.hooksPath and run an existing hook first.
+
+Example:
+  gmc hook install
+  gmc hook install --commit-msg --fix
+  gmc hook uninstall --commit-msg`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the git hook in the current repository",
+	Args:  cobra.NoArgs,
+	Run:   hookHandler.InstallCommand(&hookCommitMsg, &hookFix),
+}
+
+var hookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the git hook and restore the one it replaced",
+	Args:  cobra.NoArgs,
+	Run:   hookHandler.UninstallCommand(&hookCommitMsg),
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:    "run <message-file> [source] [sha]",
+	Short:  "Run as the git hook; called by the installed hook",
+	Args:   cobra.RangeArgs(1, 3),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch hookName {
+		case service.CommitMsgHook:
+			// gmc's own commits were linted before they were confirmed
+			if !service.ShouldLintMessage() {
+				return
+			}
+			lintFix = hookFix
+			lintCmd.Run(cmd, args[:1])
+		case service.PrepareCommitMsgHook:
+			// Merges, squashes, amends and -m commits already have their message
+			source := ""
+			if len(args) > 1 {
+				source = args[1]
+			}
+			if !service.ShouldPrepareMessage(source) {
+				return
+			}
+
+			// git is committing what is staged and will open the editor itself
+			messageFile = args[0]
+			stageAll, autoSelect, push = false, false, false
+			noConfirm, quiet = true, true
+			RootCmd.Run(cmd, nil)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookInstallCmd, hookUninstallCmd, hookRunCmd)
+
+	for _, c := range []*cobra.Command{hookInstallCmd, hookUninstallCmd} {
+		c.Flags().
+			BoolVar(&hookCommitMsg, "commit-msg", hookCommitMsg, "the commit-msg hook that lints the author's message")
+	}
+	hookInstallCmd.Flags().
+		BoolVar(&hookFix, "fix", hookFix, "let the commit-msg hook rewrite a failing message with AI instead of rejecting it")
+	hookRunCmd.Flags().
+		StringVar(&hookName, "hook", hookName, "name of the git hook being run")
+	hookRunCmd.Flags().
+		BoolVar(&hookFix, "fix", hookFix, "rewrite a failing message instead of rejecting it")
+}
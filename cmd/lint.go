@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tfkhdyt/geminicommit/internal/delivery/cli/handler"
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+var (
+	lintHandler   = handler.NewLintHandler()
+	lintRange     string
+	lintStdin     = false
+	lintAISuggest = false
+	lintFix       = false
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [file]",
+	Short: "Check commit messages against the commit rules",
+	Long: `Check commit messages against the same rules geminicommit applies to generated
+messages, including the repository's commitlint config. Every violation is
+reported with its rule ID and the command exits non-zero, so it can run in CI.
+
+Example:
+  gmc lint .git/COMMIT_EDITMSG
+  gmc lint --range origin/main..HEAD
+  echo "fix: handle empty body" | gmc lint --stdin
+  gmc lint --range origin/main..HEAD --ai-suggest
+  gmc lint .git/COMMIT_EDITMSG --fix`,
+	Args: cobra.MaximumNArgs(1),
+	Run: lintHandler.LintCommand(
+		context.Background(),
+		&lintRange,
+		&lintStdin,
+		&lintAISuggest,
+		&lintFix,
+		&model,
+		&subjectMaxLength,
+		&bodyWrap,
+		&language,
+		&customBaseUrl,
+	),
+}
+
+func init() {
+	RootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().
+		StringVar(&lintRange, "range", "", "lint every commit in a revision range, e.g. origin/main..HEAD")
+	lintCmd.Flags().
+		BoolVar(&lintStdin, "stdin", lintStdin, "read the commit message from standard input")
+	lintCmd.Flags().
+		BoolVar(&lintAISuggest, "ai-suggest", lintAISuggest, "ask the AI for a corrected message when linting fails")
+	lintCmd.Flags().
+		BoolVar(&lintFix, "fix", lintFix, "rewrite the message file with an AI-corrected message when linting fails")
+	lintCmd.Flags().
+		StringVarP(&model, "model", "m", service.DefaultModel, "google gemini model to use")
+	lintCmd.Flags().
+		IntVar(&subjectMaxLength, "subject-max-length", subjectMaxLength, "maximum length of the commit message subject line")
+	lintCmd.Flags().
+		IntVar(&bodyWrap, "body-wrap", bodyWrap, "maximum length of commit message body lines; 0 to disable")
+	lintCmd.Flags().
+		StringVarP(&language, "language", "", language, "language of the suggested commit message")
+	lintCmd.Flags().
+		StringVarP(&customBaseUrl, "baseurl", "", service.DefaultBaseUrl, "specify custom url for Google Gemini Pro API")
+	lintCmd.MarkFlagsMutuallyExclusive("range", "stdin")
+	lintCmd.MarkFlagsMutuallyExclusive("fix", "ai-suggest")
+}
@@ -32,8 +32,18 @@ var prCmd = &cobra.Command{
 		&maxLength,
 		&language,
 		&userContext,
+		&contextFiles,
+		&contextCmds,
 		&draft,
 		&customBaseUrl,
+		&diffAlgorithm,
+		&diffContext,
+		&diffFuncContext,
+		&diffIgnoreSpace,
+		&diffRenames,
+		&secretAction,
+		&secretPatterns,
+		&privacy,
 	),
 }
 
@@ -64,8 +74,14 @@ func init() {
 		StringVarP(&language, "language", "", language, "language of the pull request title")
 	prCmd.Flags().
 		StringVarP(&userContext, "context", "c", "", "additional context to be added to the pull request title")
+	prCmd.Flags().
+		StringArrayVar(&contextFiles, "context-file", nil, "file whose contents are added to the context, e.g. a design doc (repeatable)")
+	prCmd.Flags().
+		StringArrayVar(&contextCmds, "context-cmd", nil, "command whose output is added to the context, e.g. \"go test ./...\" (repeatable)")
 	prCmd.Flags().
 		BoolVar(&draft, "draft", draft, "create a draft pull request")
+	prCmd.Flags().
+		StringVar(&privacy, "privacy", privacy, "how much of the branch's changes to send: full (diff), symbols (file stats and declaration names) or stats (file stats only)")
 	prCmd.Flags().
 		StringVarP(&customBaseUrl, "baseurl", "", service.DefaultBaseUrl, "specify custom url for Google Gemini Pro API")
 }
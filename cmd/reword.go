@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tfkhdyt/geminicommit/internal/delivery/cli/handler"
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+var (
+	rewordHandler = handler.NewRewordHandler()
+	rewordForce   = false
+)
+
+// rewordCmd represents the reword command
+var rewordCmd = &cobra.Command{
+	Use:   "reword <range>",
+	Short: "Rewrite the messages of existing commits",
+	Long: `Generate a new message for every commit in a revision range from its own
+diff, review them one by one, then rewrite the branch without an interactive
+rebase. Trees, authors and trailers are kept, and the old branch tip is saved
+in ` + service.RewordBackupRef + `. The range must end at HEAD and contain no merges.
+
+Example:
+  gmc reword origin/main..HEAD
+  gmc reword HEAD~3..HEAD --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: rewordHandler.RewordCommand(
+		context.Background(),
+		&userContext,
+		&model,
+		&noConfirm,
+		&dryRun,
+		&rewordForce,
+		&subjectMaxLength,
+		&bodyWrap,
+		&language,
+		&customBaseUrl,
+		&diffExclude,
+		&diffAlgorithm,
+		&diffContext,
+		&diffFuncContext,
+		&diffIgnoreSpace,
+		&diffRenames,
+		&diffFullFilesKB,
+		&secretAction,
+		&secretPatterns,
+		&privacy,
+	),
+}
+
+func init() {
+	RootCmd.AddCommand(rewordCmd)
+
+	rewordCmd.Flags().
+		BoolVar(&rewordForce, "force", rewordForce, "reword even if the commits were already pushed")
+	rewordCmd.Flags().
+		BoolVarP(&noConfirm, "yes", "y", noConfirm, "accept every generated message without review")
+	rewordCmd.Flags().
+		BoolVar(&dryRun, "dry-run", dryRun, "generate and review the messages without rewriting history")
+	rewordCmd.Flags().
+		StringVarP(&userContext, "context", "c", "", "additional context for all the commit messages")
+	rewordCmd.Flags().
+		StringVarP(&model, "model", "m", service.DefaultModel, "google gemini model to use")
+	rewordCmd.Flags().
+		StringVarP(&language, "language", "", language, "language of the commit messages")
+	rewordCmd.Flags().
+		StringVar(&privacy, "privacy", privacy, "how much of each commit to send: full (diff), symbols (file stats and declaration names) or stats (file stats only)")
+	rewordCmd.Flags().
+		StringVarP(&customBaseUrl, "baseurl", "", service.DefaultBaseUrl, "specify custom url for Google Gemini Pro API")
+}
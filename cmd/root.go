@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 
@@ -18,23 +19,59 @@ import (
 )
 
 var (
-	cfgFile       string
-	stageAll      = false
-	autoSelect    = false
-	userContext   string
-	model         string
-	noConfirm     = false
-	quiet         = false
-	push          = false
-	dryRun        = false
-	showDiff      = false
-	maxLength     = 72
-	language      = "english"
-	issue         string
-	issueFooter   = "Refs"
-	noVerify      = false
-	customBaseUrl string
-	rootHandler   = handler.NewRootHandler()
+	cfgFile          string
+	stageAll         = false
+	autoSelect       = false
+	userContext      string
+	contextFiles     []string
+	contextCmds      []string
+	model            string
+	noConfirm        = false
+	quiet            = false
+	push             = false
+	dryRun           = false
+	showDiff         = false
+	maxLength        = 72
+	subjectMaxLength = service.DefaultSubjectMaxLength
+	bodyWrap         = service.DefaultBodyWrap
+	language         = "english"
+	commitType       string
+	commitScope      string
+	breaking         = false
+	issues           []string
+	issueFooter      = "Refs"
+	issuePatterns    []string
+	issueTracker     string
+	issueURL         string
+	issuePlacement   = service.IssuePlacementFooter
+	issueFetch       = false
+	issueRepo        string
+	issueToken       string
+	issueAPIURL      string
+	signoff          = false
+	coAuthors        []string
+	coAuthorAliases  = map[string]string{}
+	commitTrailers   []string
+	noVerify         = false
+	customBaseUrl    string
+	scopeMap         = map[string]string{}
+	branchTypes      = map[string]string{}
+	enforceBranch    = false
+	diffExclude      []string
+	diffMaxFileKB    = service.DefaultMaxFileKB
+	diffAlgorithm    = service.DefaultDiffAlgorithm
+	diffContext      = service.DefaultDiffContextLines
+	diffFuncContext  = false
+	diffIgnoreSpace  = false
+	diffRenames      = service.DiffRenames
+	diffFullFilesKB  = 0
+	secretAction     = service.SecretActionWarn
+	secretPatterns   []string
+	privacy          = service.PrivacyFull
+	neighbors        = service.NeighborsTracked
+	messageFile      string  // set by `gmc hook run` to write the message instead of committing
+	amend            = false // set by `gmc amend`
+	rootHandler      = handler.NewRootHandler()
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -54,18 +91,54 @@ var RootCmd = &cobra.Command{
 		&stageAll,
 		&autoSelect,
 		&userContext,
+		&contextFiles,
+		&contextCmds,
 		&model,
 		&noConfirm,
 		&quiet,
 		&push,
 		&dryRun,
 		&showDiff,
-		&maxLength,
+		&subjectMaxLength,
+		&bodyWrap,
 		&language,
-		&issue,
+		&commitType,
+		&commitScope,
+		&breaking,
+		&issues,
 		&issueFooter,
+		&issuePatterns,
+		&issueTracker,
+		&issueURL,
+		&issuePlacement,
+		&issueFetch,
+		&issueRepo,
+		&issueToken,
+		&issueAPIURL,
+		&signoff,
+		&coAuthors,
+		&coAuthorAliases,
+		&commitTrailers,
 		&noVerify,
 		&customBaseUrl,
+		&scopeMap,
+		&branchTypes,
+		&enforceBranch,
+		&diffExclude,
+		&diffMaxFileKB,
+		&diffAlgorithm,
+		&diffContext,
+		&diffFuncContext,
+		&diffIgnoreSpace,
+		&diffRenames,
+		&diffFullFilesKB,
+		&secretAction,
+		&secretPatterns,
+		&privacy,
+		&neighbors,
+		&messageFile,
+		&amend,
+		&amendForce,
 	),
 }
 
@@ -100,6 +173,10 @@ func init() {
 		BoolVarP(&push, "push", "p", push, "push committed changes to remote repository")
 	RootCmd.Flags().
 		StringVarP(&userContext, "context", "c", "", "additional context to be added to the commit message")
+	RootCmd.Flags().
+		StringArrayVar(&contextFiles, "context-file", nil, "file whose contents are added to the context, e.g. a design doc (repeatable)")
+	RootCmd.Flags().
+		StringArrayVar(&contextCmds, "context-cmd", nil, "command whose output is added to the context, e.g. \"go test ./...\" (repeatable)")
 	RootCmd.Flags().
 		StringVarP(&model, "model", "m", service.DefaultModel, "google gemini model to use")
 	RootCmd.Flags().
@@ -107,17 +184,33 @@ func init() {
 	RootCmd.Flags().
 		BoolVarP(&showDiff, "show-diff", "", showDiff, "show the diff before committing")
 	RootCmd.Flags().
-		IntVarP(&maxLength, "max-length", "l", maxLength, "maximum length of the commit message")
+		IntVarP(&maxLength, "max-length", "l", maxLength, "deprecated alias of --subject-max-length")
+	RootCmd.Flags().
+		IntVar(&subjectMaxLength, "subject-max-length", subjectMaxLength, "maximum length of the commit message subject line")
+	RootCmd.Flags().
+		IntVar(&bodyWrap, "body-wrap", bodyWrap, "column to hard-wrap the commit message body at; 0 to disable")
 	RootCmd.Flags().
 		StringVarP(&language, "language", "", language, "language of the commit message")
 	RootCmd.Flags().
-		StringVarP(&issue, "issue", "i", "", "issue number or title")
+		StringVar(&commitType, "type", "", "commit type to use, e.g. feat or fix; Gemini only writes the description")
+	RootCmd.Flags().
+		StringVar(&commitScope, "scope", "", "commit scope to use")
+	RootCmd.Flags().
+		BoolVar(&breaking, "breaking", breaking, "mark the commit as a breaking change and require a BREAKING CHANGE footer")
+	RootCmd.Flags().
+		StringArrayVarP(&issues, "issue", "i", nil, "issue number or key, optionally with a footer keyword like closes:34 (repeatable)")
 	RootCmd.Flags().
 		StringVar(&issueFooter, "issue-footer", issueFooter, "keyword for the auto-appended issue trailer, e.g. Refs/Closes/Fixes; empty to disable")
+	RootCmd.Flags().
+		BoolVarP(&signoff, "signoff", "s", signoff, "add a Signed-off-by trailer for the committer")
+	RootCmd.Flags().
+		StringArrayVar(&coAuthors, "co-author", nil, "add a Co-authored-by trailer, as \"Name <email>\" or an alias from [coauthors] (repeatable)")
 	RootCmd.Flags().
 		BoolVarP(&noVerify, "no-verify", "", noVerify, "skip git commit-msg hook verification")
 	RootCmd.Flags().
 		StringVarP(&customBaseUrl, "baseurl", "", service.DefaultBaseUrl, "specify custom url for Google Gemini Pro API")
+	RootCmd.Flags().
+		StringVar(&privacy, "privacy", privacy, "how much of the change to send: full (diff), symbols (file stats and declaration names) or stats (file stats only)")
 
 	// Bind flags to viper config keys
 	// [api]
@@ -126,7 +219,10 @@ func init() {
 	// [commit]
 	viper.BindPFlag("commit.language", RootCmd.Flags().Lookup("language"))
 	viper.BindPFlag("commit.max_length", RootCmd.Flags().Lookup("max-length"))
+	viper.BindPFlag("commit.subject_max_length", RootCmd.Flags().Lookup("subject-max-length"))
+	viper.BindPFlag("commit.body_wrap", RootCmd.Flags().Lookup("body-wrap"))
 	viper.BindPFlag("commit.issue_footer", RootCmd.Flags().Lookup("issue-footer"))
+	viper.BindPFlag("commit.signoff", RootCmd.Flags().Lookup("signoff"))
 	// [behavior]
 	viper.BindPFlag("behavior.stage_all", RootCmd.Flags().Lookup("all"))
 	viper.BindPFlag("behavior.auto_select", RootCmd.Flags().Lookup("auto"))
@@ -136,6 +232,7 @@ func init() {
 	viper.BindPFlag("behavior.dry_run", RootCmd.Flags().Lookup("dry-run"))
 	viper.BindPFlag("behavior.show_diff", RootCmd.Flags().Lookup("show-diff"))
 	viper.BindPFlag("behavior.no_verify", RootCmd.Flags().Lookup("no-verify"))
+	viper.BindPFlag("behavior.privacy", RootCmd.Flags().Lookup("privacy"))
 }
 
 // applyConfigDefaults applies config values to variables if flags are not explicitly set
@@ -156,9 +253,82 @@ func applyConfigDefaults(cmd *cobra.Command) {
 	if !flags.Changed("max-length") && viper.IsSet("commit.max_length") {
 		maxLength = viper.GetInt("commit.max_length")
 	}
+	if !flags.Changed("subject-max-length") {
+		// max_length used to cap the subject, so keep honouring it until it's replaced
+		if viper.InConfig("commit.subject_max_length") {
+			subjectMaxLength = viper.GetInt("commit.subject_max_length")
+		} else if flags.Changed("max-length") || viper.InConfig("commit.max_length") {
+			subjectMaxLength = maxLength
+		}
+	}
+	if !flags.Changed("body-wrap") && viper.IsSet("commit.body_wrap") {
+		bodyWrap = viper.GetInt("commit.body_wrap")
+	}
 	if !flags.Changed("issue-footer") && viper.IsSet("commit.issue_footer") {
 		issueFooter = viper.GetString("commit.issue_footer")
 	}
+	if !flags.Changed("signoff") && viper.IsSet("commit.signoff") {
+		signoff = viper.GetBool("commit.signoff")
+	}
+	commitTrailers = config.GetList("commit.trailers")
+	if viper.IsSet("commit.enforce_branch_type") {
+		enforceBranch = viper.GetBool("commit.enforce_branch_type")
+	}
+	// [issue]
+	issuePatterns = config.GetList("issue.patterns")
+	if viper.IsSet("issue.tracker") {
+		issueTracker = viper.GetString("issue.tracker")
+	}
+	if viper.IsSet("issue.url") {
+		issueURL = viper.GetString("issue.url")
+	}
+	if viper.IsSet("issue.placement") {
+		issuePlacement = viper.GetString("issue.placement")
+	}
+	if viper.IsSet("issue.fetch") {
+		issueFetch = viper.GetBool("issue.fetch")
+	}
+	issueRepo = viper.GetString("issue.repo")
+	issueToken = viper.GetString("issue.token")
+	issueAPIURL = viper.GetString("issue.api_url")
+	// [coauthors]
+	coAuthorAliases = flattenStringMap(viper.Get("coauthors"), "")
+	// [scopes]
+	scopeMap = flattenStringMap(viper.Get("scopes"), "")
+	// [branch_types]
+	branchTypes = flattenStringMap(viper.Get("branch_types"), "")
+	// [diff]
+	diffExclude = viper.GetStringSlice("diff.exclude")
+	if viper.IsSet("diff.max_file_kb") {
+		diffMaxFileKB = viper.GetInt("diff.max_file_kb")
+	}
+	if viper.IsSet("diff.algorithm") {
+		diffAlgorithm = viper.GetString("diff.algorithm")
+	}
+	if viper.IsSet("diff.context_lines") {
+		diffContext = viper.GetInt("diff.context_lines")
+	}
+	if viper.IsSet("diff.function_context") {
+		diffFuncContext = viper.GetBool("diff.function_context")
+	}
+	if viper.IsSet("diff.ignore_whitespace") {
+		diffIgnoreSpace = viper.GetBool("diff.ignore_whitespace")
+	}
+	if viper.IsSet("diff.renames") {
+		diffRenames = viper.GetString("diff.renames")
+	}
+	if viper.IsSet("diff.full_files_kb") {
+		diffFullFilesKB = viper.GetInt("diff.full_files_kb")
+	}
+	// [context]
+	if viper.IsSet("context.neighbors") {
+		neighbors = viper.GetString("context.neighbors")
+	}
+	// [secrets]
+	if viper.IsSet("secrets.action") {
+		secretAction = viper.GetString("secrets.action")
+	}
+	secretPatterns = config.GetList("secrets.patterns")
 	// [behavior]
 	if !flags.Changed("all") && viper.IsSet("behavior.stage_all") {
 		stageAll = viper.GetBool("behavior.stage_all")
@@ -184,6 +354,30 @@ func applyConfigDefaults(cmd *cobra.Command) {
 	if !flags.Changed("no-verify") && viper.IsSet("behavior.no_verify") {
 		noVerify = viper.GetBool("behavior.no_verify")
 	}
+	if !flags.Changed("privacy") && viper.IsSet("behavior.privacy") {
+		privacy = viper.GetString("behavior.privacy")
+	}
+}
+
+// flattenStringMap turns a config table into key/value pairs. Unquoted dotted keys
+// (e.g. *.md = "docs") come back as nested tables and are joined again here.
+func flattenStringMap(value any, prefix string) map[string]string {
+	result := map[string]string{}
+	table, ok := value.(map[string]any)
+	if !ok {
+		return result
+	}
+	for key, v := range table {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]any); ok {
+			maps.Copy(result, flattenStringMap(nested, key))
+		} else {
+			result[key] = fmt.Sprint(v)
+		}
+	}
+	return result
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+	"github.com/tfkhdyt/geminicommit/internal/usecase"
+)
+
+type HookHandler struct {
+	useCase *usecase.HookUsecase
+}
+
+func NewHookHandler() *HookHandler {
+	return &HookHandler{useCase: usecase.NewHookUsecase()}
+}
+
+func (h *HookHandler) InstallCommand(commitMsg *bool, fix *bool) func(*cobra.Command, []string) {
+	return func(_ *cobra.Command, _ []string) {
+		err := h.useCase.InstallCommand(hookName(*commitMsg), *fix)
+		cobra.CheckErr(err)
+	}
+}
+
+func (h *HookHandler) UninstallCommand(commitMsg *bool) func(*cobra.Command, []string) {
+	return func(_ *cobra.Command, _ []string) {
+		err := h.useCase.UninstallCommand(hookName(*commitMsg))
+		cobra.CheckErr(err)
+	}
+}
+
+func hookName(commitMsg bool) string {
+	if commitMsg {
+		return service.CommitMsgHook
+	}
+	return service.PrepareCommitMsgHook
+}
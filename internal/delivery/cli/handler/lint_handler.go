@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tfkhdyt/geminicommit/internal/usecase"
+)
+
+type LintHandler struct {
+	useCase *usecase.LintUsecase
+}
+
+func NewLintHandler() *LintHandler {
+	return &LintHandler{useCase: usecase.NewLintUsecase()}
+}
+
+func (l *LintHandler) LintCommand(
+	ctx context.Context,
+	revisionRange *string,
+	stdin *bool,
+	aiSuggest *bool,
+	fix *bool,
+	model *string,
+	subjectMaxLength *int,
+	bodyWrap *int,
+	language *string,
+	customBaseUrl *string,
+) func(*cobra.Command, []string) {
+	return func(_ *cobra.Command, args []string) {
+		file := ""
+		if len(args) > 0 {
+			file = args[0]
+		}
+
+		apiKey := viper.GetString("api.key")
+		if (*aiSuggest || *fix) && apiKey == "" {
+			fmt.Println(
+				"Error: API key is still empty, run this command to set your API key",
+			)
+			fmt.Print("\n")
+			color.New(color.Bold).Print("geminicommit config key set ")
+			color.New(color.Italic, color.Bold).Print("api_key\n\n")
+			os.Exit(1)
+		}
+
+		err := l.useCase.LintCommand(
+			ctx,
+			apiKey,
+			file,
+			revisionRange,
+			stdin,
+			aiSuggest,
+			fix,
+			model,
+			subjectMaxLength,
+			bodyWrap,
+			language,
+			customBaseUrl,
+		)
+		cobra.CheckErr(err)
+	}
+}
@@ -33,8 +33,18 @@ func (p *PRHandler) PRCommand(
 	maxLength *int,
 	language *string,
 	userContext *string,
+	contextFiles *[]string,
+	contextCmds *[]string,
 	draft *bool,
 	customBaseUrl *string,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
 ) func(*cobra.Command, []string) {
 	return func(_ *cobra.Command, _ []string) {
 		if *quiet && !*noConfirm {
@@ -63,8 +73,18 @@ func (p *PRHandler) PRCommand(
 			maxLength,
 			language,
 			userContext,
+			contextFiles,
+			contextCmds,
 			draft,
 			customBaseUrl,
+			diffAlgorithm,
+			diffContext,
+			diffFuncContext,
+			diffIgnoreSpace,
+			diffRenames,
+			secretAction,
+			secretPatterns,
+			privacy,
 		)
 		cobra.CheckErr(err)
 	}
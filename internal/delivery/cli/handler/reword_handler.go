@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tfkhdyt/geminicommit/internal/usecase"
+)
+
+type RewordHandler struct {
+	useCase *usecase.RewordUsecase
+}
+
+func NewRewordHandler() *RewordHandler {
+	return &RewordHandler{useCase: usecase.NewRewordUsecase()}
+}
+
+func (r *RewordHandler) RewordCommand(
+	ctx context.Context,
+	userContext *string,
+	model *string,
+	noConfirm *bool,
+	dryRun *bool,
+	force *bool,
+	subjectMaxLength *int,
+	bodyWrap *int,
+	language *string,
+	customBaseUrl *string,
+	diffExclude *[]string,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	diffFullFilesKB *int,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
+) func(*cobra.Command, []string) {
+	return func(_ *cobra.Command, args []string) {
+		apiKey := viper.GetString("api.key")
+		if apiKey == "" {
+			fmt.Println(
+				"Error: API key is still empty, run this command to set your API key",
+			)
+			fmt.Print("\n")
+			color.New(color.Bold).Print("geminicommit config key set ")
+			color.New(color.Italic, color.Bold).Print("api_key\n\n")
+			os.Exit(1)
+		}
+
+		err := r.useCase.RewordCommand(
+			ctx,
+			apiKey,
+			args[0],
+			userContext,
+			model,
+			noConfirm,
+			dryRun,
+			force,
+			subjectMaxLength,
+			bodyWrap,
+			language,
+			customBaseUrl,
+			diffExclude,
+			diffAlgorithm,
+			diffContext,
+			diffFuncContext,
+			diffIgnoreSpace,
+			diffRenames,
+			diffFullFilesKB,
+			secretAction,
+			secretPatterns,
+			privacy,
+		)
+		cobra.CheckErr(err)
+	}
+}
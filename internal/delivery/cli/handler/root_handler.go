@@ -25,18 +25,54 @@ func (r *RootHandler) RootCommand(
 	stageAll *bool,
 	autoSelect *bool,
 	userContext *string,
+	contextFiles *[]string,
+	contextCmds *[]string,
 	model *string,
 	noConfirm *bool,
 	quiet *bool,
 	push *bool,
 	dryRun *bool,
 	showDiff *bool,
-	maxLength *int,
+	subjectMaxLength *int,
+	bodyWrap *int,
 	language *string,
-	issue *string,
+	commitType *string,
+	commitScope *string,
+	breaking *bool,
+	issues *[]string,
 	issueFooter *string,
+	issuePatterns *[]string,
+	issueTracker *string,
+	issueURL *string,
+	issuePlacement *string,
+	issueFetch *bool,
+	issueRepo *string,
+	issueToken *string,
+	issueAPIURL *string,
+	signoff *bool,
+	coAuthors *[]string,
+	coAuthorAliases *map[string]string,
+	commitTrailers *[]string,
 	noVerify *bool,
 	customBaseUrl *string,
+	scopeMap *map[string]string,
+	branchTypes *map[string]string,
+	enforceBranchType *bool,
+	diffExclude *[]string,
+	diffMaxFileKB *int,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	diffFullFilesKB *int,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
+	neighbors *string,
+	messageFile *string,
+	amend *bool,
+	force *bool,
 ) func(*cobra.Command, []string) {
 	return func(_ *cobra.Command, _ []string) {
 		if *quiet && !*noConfirm {
@@ -45,6 +81,12 @@ func (r *RootHandler) RootCommand(
 
 		apiKey := viper.GetString("api.key")
 		if apiKey == "" {
+			// Under a git hook a failure must not block the commit, git just
+			// opens the editor without a message
+			if *messageFile != "" {
+				color.New(color.FgYellow).Fprintln(os.Stderr, "geminicommit: API key is not set, skipping message generation")
+				return
+			}
 			fmt.Println(
 				"Error: API key is still empty, run this command to set your API key",
 			)
@@ -54,7 +96,11 @@ func (r *RootHandler) RootCommand(
 			os.Exit(1)
 		}
 
-		err := r.useCase.RootCommand(ctx, apiKey, stageAll, autoSelect, userContext, model, noConfirm, quiet, push, dryRun, showDiff, maxLength, language, issue, issueFooter, noVerify, customBaseUrl)
+		err := r.useCase.RootCommand(ctx, apiKey, stageAll, autoSelect, userContext, contextFiles, contextCmds, model, noConfirm, quiet, push, dryRun, showDiff, subjectMaxLength, bodyWrap, language, commitType, commitScope, breaking, issues, issueFooter, issuePatterns, issueTracker, issueURL, issuePlacement, issueFetch, issueRepo, issueToken, issueAPIURL, signoff, coAuthors, coAuthorAliases, commitTrailers, noVerify, customBaseUrl, scopeMap, branchTypes, enforceBranchType, diffExclude, diffMaxFileKB, diffAlgorithm, diffContext, diffFuncContext, diffIgnoreSpace, diffRenames, diffFullFilesKB, secretAction, secretPatterns, privacy, neighbors, messageFile, amend, force)
+		if err != nil && *messageFile != "" {
+			color.New(color.FgYellow).Fprintf(os.Stderr, "geminicommit: %v\n", err)
+			return
+		}
 		cobra.CheckErr(err)
 	}
 }
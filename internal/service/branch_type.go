@@ -0,0 +1,40 @@
+package service
+
+import (
+	"maps"
+	"strings"
+)
+
+// DefaultBranchTypes maps common branch prefixes to the commit type they imply
+var DefaultBranchTypes = map[string]string{
+	"feature":  "feat",
+	"feat":     "feat",
+	"fix":      "fix",
+	"bugfix":   "fix",
+	"hotfix":   "fix",
+	"chore":    "chore",
+	"docs":     "docs",
+	"refactor": "refactor",
+	"perf":     "perf",
+	"test":     "test",
+	"ci":       "ci",
+	"build":    "build",
+}
+
+// BranchCommitType returns the commit type implied by the branch's prefix
+// (feature/login implies feat), or "" when the branch doesn't follow a known
+// scheme. Entries of mapping are added to DefaultBranchTypes; an empty type
+// turns a default prefix off.
+func BranchCommitType(branch string, mapping map[string]string) string {
+	prefix, _, ok := strings.Cut(branch, "/")
+	if !ok {
+		return ""
+	}
+
+	types := maps.Clone(DefaultBranchTypes)
+	for key, commitType := range mapping {
+		// viper lowercases config keys, so prefixes are matched case-insensitively
+		types[strings.ToLower(key)] = commitType
+	}
+	return types[strings.ToLower(prefix)]
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBranchCommitType(t *testing.T) {
+	mapping := map[string]string{"bug": "fix", "release": "chore", "docs": ""}
+	cases := []struct {
+		branch string
+		want   string
+	}{
+		{"feature/login", "feat"},
+		{"feat/PROJ-42-login", "feat"},
+		{"fix/crash", "fix"},
+		{"Hotfix/1.2.1", "fix"},
+		{"bugfix/null-pointer", "fix"},
+		{"chore/bump-deps", "chore"},
+		{"bug/123", "fix"},
+		{"release/2.0", "chore"},
+		{"docs/readme", ""},
+		{"main", ""},
+		{"feature-login", ""},
+		{"spike/idea", ""},
+	}
+	for _, tc := range cases {
+		if got := BranchCommitType(tc.branch, mapping); got != tc.want {
+			t.Errorf("BranchCommitType(%q) = %q, want %q", tc.branch, got, tc.want)
+		}
+	}
+}
+
+func TestLintRules_WithBranchType(t *testing.T) {
+	hinted := DefaultLintRules(72, 72).WithBranchType("fix", false)
+	if hinted.PreferredType != "fix" || len(hinted.Types) != len(DefaultCommitTypes) {
+		t.Fatalf("WithBranchType(hint) = %+v", hinted)
+	}
+	if !strings.Contains(hinted.PromptSection(), `Use the type "fix"`) {
+		t.Fatalf("PromptSection() = %q, want a type hint", hinted.PromptSection())
+	}
+	if got := LintCommitMessage("feat: add login", hinted); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, a hint must not reject other types", got)
+	}
+
+	enforced := DefaultLintRules(72, 72).WithBranchType("fix", true)
+	got := lintRuleIDs(LintCommitMessage("feat: add login", enforced))
+	if !slices.Equal(got, []string{"type-enum"}) {
+		t.Fatalf("LintCommitMessage() rules = %v, want [type-enum]", got)
+	}
+	if strings.Contains(enforced.PromptSection(), "unless") {
+		t.Fatalf("PromptSection() = %q, an enforced type is not a hint", enforced.PromptSection())
+	}
+}
+
+func TestLintRules_WithBranchTypeRespectsTypeEnum(t *testing.T) {
+	rules := &LintRules{Types: []string{"feat", "fix"}}
+	if got := rules.WithBranchType("chore", true); got != rules {
+		t.Fatalf("WithBranchType() = %+v, want rules unchanged", got)
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Privacy modes decide how much of the change is sent to Gemini
+const (
+	PrivacyFull    = "full"
+	PrivacySymbols = "symbols"
+	PrivacyStats   = "stats"
+)
+
+// ValidPrivacyMode reports whether mode is one of the privacy modes
+func ValidPrivacyMode(mode string) bool {
+	return mode == PrivacyFull || mode == PrivacySymbols || mode == PrivacyStats
+}
+
+// FileChange describes one changed file without its content
+type FileChange struct {
+	Status  string // A, M, D, R, C or T as in `git diff --name-status`
+	Path    string
+	OldPath string // set for renames and copies
+	Added   int
+	Deleted int
+	Binary  bool
+	Symbols *SymbolChanges
+	// Untracked is set for new files git doesn't know about yet
+	Untracked bool
+}
+
+// parseNameStatus parses `git diff --name-status -z` output
+func parseNameStatus(output string) []FileChange {
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+
+	var changes []FileChange
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		if status == "" || i+1 >= len(fields) {
+			break
+		}
+		change := FileChange{Status: status[:1]}
+		if (change.Status == "R" || change.Status == "C") && i+2 < len(fields) {
+			change.OldPath, change.Path = fields[i+1], fields[i+2]
+			i += 2
+		} else {
+			change.Path = fields[i+1]
+			i++
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// applyNumstat fills in line counts from `git diff --numstat -z` output
+func applyNumstat(changes []FileChange, output string) {
+	byPath := map[string]*FileChange{}
+	for i := range changes {
+		byPath[changes[i].Path] = &changes[i]
+	}
+
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		counts := strings.SplitN(fields[i], "\t", 3)
+		if len(counts) != 3 {
+			continue
+		}
+		path := counts[2]
+		if path == "" && i+2 < len(fields) {
+			// Renames list the old and new path as separate fields
+			path = fields[i+2]
+			i += 2
+		}
+
+		change, ok := byPath[path]
+		if !ok {
+			continue
+		}
+		if counts[0] == "-" {
+			change.Binary = true
+			continue
+		}
+		change.Added, _ = strconv.Atoi(counts[0])
+		change.Deleted, _ = strconv.Atoi(counts[1])
+	}
+}
+
+// FormatFileChanges renders changes for the prompt, labelled so the model knows
+// the source code was deliberately left out
+func FormatFileChanges(changes []FileChange, privacy string) string {
+	var b strings.Builder
+	if privacy == PrivacySymbols {
+		b.WriteString("Source code is withheld for privacy. Only file statuses, line counts and the names of added, removed and changed declarations are shown.\n\n")
+	} else {
+		b.WriteString("Source code is withheld for privacy. Only file statuses and line counts are shown.\n\n")
+	}
+
+	for _, c := range changes {
+		path := c.Path
+		if c.OldPath != "" {
+			path = c.OldPath + " -> " + c.Path
+		}
+		counts := fmt.Sprintf("+%d -%d", c.Added, c.Deleted)
+		if c.Binary {
+			counts = "binary"
+		}
+		fmt.Fprintf(&b, "%s  %s  %s\n", c.Status, path, counts)
+
+		if c.Symbols == nil {
+			continue
+		}
+		for _, group := range []struct {
+			label   string
+			symbols []string
+		}{
+			{"added", c.Symbols.Added},
+			{"removed", c.Symbols.Removed},
+			{"changed", c.Symbols.Changed},
+		} {
+			if len(group.symbols) > 0 {
+				fmt.Fprintf(&b, "     %s: %s\n", group.label, formatSymbolList(group.symbols))
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,45 @@
+package service
+
+import "testing"
+
+func TestParseNameStatus(t *testing.T) {
+	changes := parseNameStatus("M\x00a.go\x00R087\x00old.go\x00new.go\x00D\x00gone.go\x00")
+	applyNumstat(changes, "3\t1\ta.go\x001\t1\t\x00old.go\x00new.go\x00-\t-\tgone.go\x00")
+
+	want := []FileChange{
+		{Status: "M", Path: "a.go", Added: 3, Deleted: 1},
+		{Status: "R", Path: "new.go", OldPath: "old.go", Added: 1, Deleted: 1},
+		{Status: "D", Path: "gone.go", Binary: true},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("parseNameStatus() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestFormatFileChanges(t *testing.T) {
+	changes := []FileChange{
+		{Status: "M", Path: "svc.go", Added: 5, Deleted: 6, Symbols: &SymbolChanges{
+			Added:   []string{"func New"},
+			Changed: []string{"func Store.Get"},
+		}},
+		{Status: "R", Path: "docs.txt", OldPath: "notes.txt"},
+		{Status: "A", Path: "logo.png", Binary: true},
+	}
+
+	want := `Source code is withheld for privacy. Only file statuses, line counts and the names of added, removed and changed declarations are shown.
+
+M  svc.go  +5 -6
+     added: func New
+     changed: func Store.Get
+R  notes.txt -> docs.txt  +0 -0
+A  logo.png  binary`
+
+	if got := FormatFileChanges(changes, PrivacySymbols); got != want {
+		t.Fatalf("FormatFileChanges() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,539 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultCommitTypes are the Conventional Commits types the system prompt allows.
+var DefaultCommitTypes = []string{
+	"feat", "fix", "refactor", "perf", "docs", "test",
+	"chore", "build", "ci", "style", "revert",
+}
+
+// LintRules describes the constraints a commit message has to satisfy.
+// Empty/zero fields disable the corresponding rule; the header grammar and
+// trailer syntax are always checked.
+type LintRules struct {
+	Types             []string
+	Scopes            []string
+	ScopeRequired     bool
+	BreakingRequired  bool // the header needs "!" and a BREAKING CHANGE footer
+	SubjectCase       []string
+	SubjectCaseNot    bool // SubjectCase lists forbidden cases instead of allowed ones
+	HeaderMaxLength   int
+	BodyMaxLineLength int
+	// PreferredType is the type implied by the branch name, suggested to the model
+	PreferredType string
+	// Disabled lists the rules commitlint turns off (level 0), Warnings those
+	// it only warns about (level 1); the latter are reported but don't fail
+	Disabled []string
+	Warnings []string
+	// Source is the config file the rules were read from, for display only.
+	Source string
+}
+
+// DefaultLintRules returns the rules geminicommit's own prompts ask for.
+func DefaultLintRules(subjectMaxLength, bodyWrap int) *LintRules {
+	return &LintRules{
+		Types:             slices.Clone(DefaultCommitTypes),
+		HeaderMaxLength:   subjectMaxLength,
+		BodyMaxLineLength: bodyWrap,
+	}
+}
+
+// Merge overlays the non-empty settings of other onto a copy of r.
+// The stricter of two length limits wins.
+func (r *LintRules) Merge(other *LintRules) *LintRules {
+	if r == nil {
+		return other
+	}
+	merged := *r
+	if other == nil {
+		return &merged
+	}
+	if len(other.Types) > 0 {
+		merged.Types = other.Types
+	}
+	if len(other.Scopes) > 0 {
+		merged.Scopes = other.Scopes
+	}
+	if len(other.SubjectCase) > 0 {
+		merged.SubjectCase = other.SubjectCase
+		merged.SubjectCaseNot = other.SubjectCaseNot
+	}
+	// The header length limit is geminicommit's own setting, so it stays
+	for _, rule := range other.Disabled {
+		switch rule {
+		case "type-enum":
+			merged.Types = nil
+		case "scope-enum":
+			merged.Scopes = nil
+		case "subject-case":
+			merged.SubjectCase = nil
+		}
+	}
+	merged.HeaderMaxLength = minLimit(merged.HeaderMaxLength, other.HeaderMaxLength)
+	merged.BodyMaxLineLength = minLimit(merged.BodyMaxLineLength, other.BodyMaxLineLength)
+	merged.Disabled = slices.Clone(r.Disabled)
+	for _, rule := range other.Disabled {
+		if rule != "header-max-length" {
+			merged.Disabled = append(merged.Disabled, rule)
+		}
+	}
+	merged.Warnings = slices.Clone(r.Warnings)
+	for _, rule := range other.Warnings {
+		// A stricter limit of geminicommit's own is still an error
+		if rule == "header-max-length" && merged.HeaderMaxLength != other.HeaderMaxLength {
+			continue
+		}
+		merged.Warnings = append(merged.Warnings, rule)
+	}
+	if other.Source != "" {
+		merged.Source = other.Source
+	}
+	return &merged
+}
+
+// WithScopes returns a copy of r restricted to scopes. A single scope becomes
+// mandatory, so every commit touching the same folder gets the same scope.
+// Scopes outside an existing scope-enum are dropped.
+func (r *LintRules) WithScopes(scopes []string) *LintRules {
+	if r == nil {
+		return r
+	}
+	if len(r.Scopes) > 0 {
+		scopes = slices.DeleteFunc(slices.Clone(scopes), func(s string) bool {
+			return !slices.Contains(r.Scopes, s)
+		})
+	}
+	if len(scopes) == 0 {
+		return r
+	}
+	restricted := *r
+	restricted.Scopes = scopes
+	restricted.ScopeRequired = len(scopes) == 1
+	return &restricted
+}
+
+// WithBranchType returns a copy of r steering the type towards commitType, the
+// one implied by the branch name: as the only allowed type when enforce is set,
+// as a strong hint otherwise. A type outside an existing type-enum is ignored.
+func (r *LintRules) WithBranchType(commitType string, enforce bool) *LintRules {
+	if r == nil || commitType == "" {
+		return r
+	}
+	if len(r.Types) > 0 && !slices.Contains(r.Types, commitType) {
+		return r
+	}
+	hinted := *r
+	hinted.PreferredType = commitType
+	if enforce {
+		hinted.Types = []string{commitType}
+	}
+	return &hinted
+}
+
+// WithOverride returns a copy of r restricted to the type and scope fixed on
+// the command line. These are the user's explicit choice, so unlike WithScopes
+// they replace any type-enum or scope-enum.
+func (r *LintRules) WithOverride(o HeaderOverride) *LintRules {
+	if r == nil || o.IsZero() {
+		return r
+	}
+	restricted := *r
+	if o.Type != "" {
+		restricted.Types = []string{o.Type}
+		restricted.PreferredType = ""
+	}
+	if o.Scope != "" {
+		restricted.Scopes = []string{o.Scope}
+		restricted.ScopeRequired = true
+	}
+	restricted.BreakingRequired = restricted.BreakingRequired || o.Breaking
+	return &restricted
+}
+
+func minLimit(a, b int) int {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+// LintViolation is a single broken rule, identified by its commitlint-style ID.
+// Warning is set for rules commitlint only warns about.
+type LintViolation struct {
+	Rule    string
+	Message string
+	Warning bool
+}
+
+func (v LintViolation) String() string {
+	if v.Warning {
+		return fmt.Sprintf("[%s] warning: %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("[%s] %s", v.Rule, v.Message)
+}
+
+// LintErrors returns the violations that fail the message, leaving out warnings
+func LintErrors(violations []LintViolation) []LintViolation {
+	var errors []LintViolation
+	for _, v := range violations {
+		if !v.Warning {
+			errors = append(errors, v)
+		}
+	}
+	return errors
+}
+
+// CommitHeader is the parsed first line of a Conventional Commits message.
+type CommitHeader struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+}
+
+var (
+	headerPattern  = regexp.MustCompile(`^(\w+)(?:\(([^()\r\n]*)\))?(!)?: (.*)$`)
+	trailerPattern = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*)(: | #)\S`)
+	trailerKeyword = regexp.MustCompile(
+		`(?i)^(refs?|close[sd]?|fix(es|ed)?|resolve[sd]?|see-also|signed-off-by|co-authored-by|reviewed-by|acked-by|breaking[ -]change)\b`,
+	)
+)
+
+// ParseCommitHeader splits "type(scope)!: subject" into its parts.
+func ParseCommitHeader(header string) (CommitHeader, bool) {
+	m := headerPattern.FindStringSubmatch(header)
+	if m == nil {
+		return CommitHeader{}, false
+	}
+	return CommitHeader{
+		Type:     m[1],
+		Scope:    m[2],
+		Breaking: m[3] == "!",
+		Subject:  m[4],
+	}, true
+}
+
+// scissorsLine marks the start of the diff git appends with `commit --verbose`
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// CleanCommitMessage strips what git itself would drop from a message file:
+// comment lines and everything below the scissors line.
+func CleanCommitMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if line == scissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+var ignoredMessagePattern = regexp.MustCompile(`^(Merge |Revert "|fixup! |squash! |amend! |Automatic merge)`)
+
+// IsIgnoredCommitMessage reports whether message was written by git or an
+// autosquash workflow and should not be linted, mirroring commitlint's defaults.
+func IsIgnoredCommitMessage(message string) bool {
+	return ignoredMessagePattern.MatchString(strings.TrimSpace(message))
+}
+
+// LintCommitMessage checks message against rules and returns every violation
+// found, leaving out rules in Disabled and marking those in Warnings as warnings.
+func LintCommitMessage(message string, rules *LintRules) []LintViolation {
+	if rules == nil {
+		return nil
+	}
+
+	var violations []LintViolation
+	for _, v := range lintMessage(message, rules) {
+		if slices.Contains(rules.Disabled, v.Rule) {
+			continue
+		}
+		v.Warning = slices.Contains(rules.Warnings, v.Rule)
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// lintMessage runs every rule against message
+func lintMessage(message string, rules *LintRules) []LintViolation {
+	message = strings.TrimSpace(message)
+	header, _, _ := strings.Cut(message, "\n")
+	var violations []LintViolation
+
+	if rules.HeaderMaxLength > 0 {
+		if n := utf8.RuneCountInString(header); n > rules.HeaderMaxLength {
+			violations = append(violations, LintViolation{
+				Rule:    "header-max-length",
+				Message: fmt.Sprintf("header is %d characters, must not exceed %d", n, rules.HeaderMaxLength),
+			})
+		}
+	}
+
+	violations = append(violations, lintBody(message, rules)...)
+
+	parsed, ok := ParseCommitHeader(header)
+	if !ok {
+		return append(violations, LintViolation{
+			Rule:    "header-format",
+			Message: `header must look like "type(scope): subject"`,
+		})
+	}
+
+	if len(rules.Types) > 0 && !slices.Contains(rules.Types, parsed.Type) {
+		violations = append(violations, LintViolation{
+			Rule:    "type-enum",
+			Message: fmt.Sprintf("type %q must be one of: %s", parsed.Type, strings.Join(rules.Types, ", ")),
+		})
+	}
+
+	if rules.ScopeRequired && parsed.Scope == "" {
+		violations = append(violations, LintViolation{
+			Rule:    "scope-empty",
+			Message: fmt.Sprintf("scope is required, use: %s", strings.Join(rules.Scopes, ", ")),
+		})
+	}
+
+	if len(rules.Scopes) > 0 && parsed.Scope != "" {
+		for _, scope := range splitScopes(parsed.Scope) {
+			if !slices.Contains(rules.Scopes, scope) {
+				violations = append(violations, LintViolation{
+					Rule:    "scope-enum",
+					Message: fmt.Sprintf("scope %q must be one of: %s", scope, strings.Join(rules.Scopes, ", ")),
+				})
+			}
+		}
+	}
+
+	if rules.BreakingRequired && !parsed.Breaking {
+		violations = append(violations, LintViolation{
+			Rule:    "header-breaking",
+			Message: `header must mark the breaking change with "!" before the colon`,
+		})
+	}
+	if rules.BreakingRequired && !hasBreakingFooter(message) {
+		violations = append(violations, LintViolation{
+			Rule:    "footer-breaking-change",
+			Message: `a "BREAKING CHANGE: <what breaks and how to migrate>" footer is required`,
+		})
+	}
+
+	if strings.TrimSpace(parsed.Subject) == "" {
+		violations = append(violations, LintViolation{
+			Rule:    "subject-empty",
+			Message: "subject may not be empty",
+		})
+	} else if len(rules.SubjectCase) > 0 {
+		matched := false
+		for _, c := range rules.SubjectCase {
+			if matchesCase(parsed.Subject, c) {
+				matched = true
+				break
+			}
+		}
+		if rules.SubjectCaseNot && matched {
+			violations = append(violations, LintViolation{
+				Rule:    "subject-case",
+				Message: fmt.Sprintf("subject must not be %s", strings.Join(rules.SubjectCase, ", ")),
+			})
+		} else if !rules.SubjectCaseNot && !matched {
+			violations = append(violations, LintViolation{
+				Rule:    "subject-case",
+				Message: fmt.Sprintf("subject must be %s", strings.Join(rules.SubjectCase, ", ")),
+			})
+		}
+	}
+
+	if strings.HasSuffix(parsed.Subject, ".") {
+		violations = append(violations, LintViolation{
+			Rule:    "subject-full-stop",
+			Message: "subject may not end with a period",
+		})
+	}
+
+	return violations
+}
+
+// lintBody checks the layout of everything below the header.
+func lintBody(message string, rules *LintRules) []LintViolation {
+	lines := strings.Split(message, "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+
+	var violations []LintViolation
+	if strings.TrimSpace(lines[1]) != "" {
+		violations = append(violations, LintViolation{
+			Rule:    "body-leading-blank",
+			Message: "the header must be followed by a blank line",
+		})
+	}
+
+	if rules.BodyMaxLineLength > 0 {
+		for i, line := range lines[1:] {
+			n := utf8.RuneCountInString(line)
+			// Unbreakable lines (URLs, long identifiers) can't be wrapped, so they are exempt
+			if n > rules.BodyMaxLineLength && len(strings.Fields(line)) > 1 && !strings.Contains(line, "://") {
+				violations = append(violations, LintViolation{
+					Rule:    "body-max-line-length",
+					Message: fmt.Sprintf("line %d is %d characters, must not exceed %d", i+2, n, rules.BodyMaxLineLength),
+				})
+			}
+		}
+	}
+
+	_, rest, _ := strings.Cut(message, "\n")
+	footer := finalParagraph(strings.TrimSpace(rest))
+	if footer == "" || !isTrailerBlock(footer) {
+		return violations
+	}
+	for _, line := range strings.Split(footer, "\n") {
+		if trailerPattern.MatchString(line) || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		violations = append(violations, LintViolation{
+			Rule:    "trailer-format",
+			Message: fmt.Sprintf(`footer line %q must be a "Token: value" or "Token #value" trailer`, line),
+		})
+	}
+
+	return violations
+}
+
+// isTrailerBlock reports whether paragraph is meant as a git trailer block: every
+// line is a trailer, a continuation, or starts with a well-known trailer keyword.
+// Prose that happens to start with "Note: ..." is left alone.
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if trailerPattern.MatchString(line) || trailerKeyword.MatchString(line) ||
+			strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// PromptSection renders the rules as instructions for the system prompt.
+func (r *LintRules) PromptSection() string {
+	if r == nil {
+		return ""
+	}
+
+	var lines []string
+	if len(r.Types) > 0 {
+		lines = append(lines, fmt.Sprintf("- type MUST be one of: %s", strings.Join(r.Types, ", ")))
+	}
+	if r.ScopeRequired && len(r.Scopes) == 1 {
+		lines = append(lines, fmt.Sprintf("- scope MUST be exactly %q", r.Scopes[0]))
+	} else if len(r.Scopes) > 0 {
+		lines = append(lines, fmt.Sprintf("- scope, if present, MUST be one of: %s", strings.Join(r.Scopes, ", ")))
+	}
+	if r.BreakingRequired {
+		lines = append(lines, `- this is a breaking change: put "!" before the colon in the header and end the message with a "BREAKING CHANGE: <what breaks and how to migrate>" footer`)
+	}
+	if len(r.SubjectCase) > 0 {
+		verb := "MUST be"
+		if r.SubjectCaseNot {
+			verb = "MUST NOT be"
+		}
+		lines = append(lines, fmt.Sprintf("- subject %s %s", verb, strings.Join(r.SubjectCase, ", ")))
+	}
+	if r.HeaderMaxLength > 0 {
+		lines = append(lines, fmt.Sprintf("- the header (first line) MUST NOT exceed %d characters", r.HeaderMaxLength))
+	}
+	if r.BodyMaxLineLength > 0 {
+		lines = append(lines, fmt.Sprintf("- body lines MUST be wrapped at %d characters", r.BodyMaxLineLength))
+	}
+
+	var section string
+	if len(lines) > 0 {
+		intro := "IMPORTANT: The repository enforces these commit message rules"
+		if r.Source != "" {
+			intro += fmt.Sprintf(" (from %s)", r.Source)
+		}
+		section = intro + ". Messages that break them are rejected:\n" + strings.Join(lines, "\n")
+	}
+	if r.PreferredType != "" && !slices.Equal(r.Types, []string{r.PreferredType}) {
+		if section != "" {
+			section += "\n\n"
+		}
+		section += fmt.Sprintf(
+			"IMPORTANT: The branch name says this is a %q change. Use the type %q unless the diff clearly shows something else.",
+			r.PreferredType, r.PreferredType,
+		)
+	}
+	return section
+}
+
+// FormatLintViolations joins violations into one bullet per line.
+func FormatLintViolations(violations []LintViolation) string {
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, "- "+v.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func splitScopes(scope string) []string {
+	return strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ',' || r == '/' || r == '\\'
+	})
+}
+
+// matchesCase reports whether s is written in the named commitlint case.
+func matchesCase(s, name string) bool {
+	first, _ := utf8.DecodeRuneInString(s)
+	if !unicode.IsLetter(first) {
+		// commitlint treats subjects starting with digits/symbols as matching every case
+		return true
+	}
+
+	switch name {
+	case "lower-case", "lowercase":
+		return s == strings.ToLower(s)
+	case "upper-case", "uppercase":
+		return s == strings.ToUpper(s)
+	case "sentence-case", "sentencecase":
+		return unicode.IsUpper(first)
+	case "start-case", "startcase":
+		for _, word := range strings.Fields(s) {
+			r, _ := utf8.DecodeRuneInString(word)
+			if unicode.IsLetter(r) && !unicode.IsUpper(r) {
+				return false
+			}
+		}
+		return true
+	case "pascal-case", "pascalcase":
+		return unicode.IsUpper(first) && !strings.ContainsAny(s, " -_")
+	case "camel-case", "camelcase":
+		return unicode.IsLower(first) && !strings.ContainsAny(s, " -_")
+	case "kebab-case", "kebabcase":
+		return s == strings.ToLower(s) && !strings.ContainsAny(s, " _")
+	case "snake-case", "snakecase":
+		return s == strings.ToLower(s) && !strings.ContainsAny(s, " -")
+	}
+	return false
+}
+
+func finalParagraph(message string) string {
+	trimmed := strings.TrimRight(message, "\n")
+	if i := strings.LastIndex(trimmed, "\n\n"); i >= 0 {
+		return trimmed[i+2:]
+	}
+	return trimmed
+}
@@ -0,0 +1,177 @@
+package service
+
+import "testing"
+
+func lintRuleIDs(violations []LintViolation) []string {
+	ids := make([]string, 0, len(violations))
+	for _, v := range violations {
+		ids = append(ids, v.Rule)
+	}
+	return ids
+}
+
+func TestParseCommitHeader(t *testing.T) {
+	got, ok := ParseCommitHeader("feat(api)!: drop v1 routes")
+	want := CommitHeader{Type: "feat", Scope: "api", Breaking: true, Subject: "drop v1 routes"}
+	if !ok || got != want {
+		t.Fatalf("ParseCommitHeader() = %+v, %v, want %+v", got, ok, want)
+	}
+}
+
+func TestParseCommitHeader_invalid(t *testing.T) {
+	if _, ok := ParseCommitHeader("Update stuff"); ok {
+		t.Fatal("ParseCommitHeader() ok = true, want false")
+	}
+}
+
+func TestLintCommitMessage_nilRules(t *testing.T) {
+	if got := LintCommitMessage("anything", nil); got != nil {
+		t.Fatalf("LintCommitMessage() = %v, want nil", got)
+	}
+}
+
+func TestLintCommitMessage_valid(t *testing.T) {
+	rules := &LintRules{
+		Types:           []string{"feat", "fix"},
+		Scopes:          []string{"api"},
+		SubjectCase:     []string{"sentence-case", "upper-case"},
+		SubjectCaseNot:  true,
+		HeaderMaxLength: 50,
+	}
+	if got := LintCommitMessage("fix(api): handle empty body\n\nDetails.", rules); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+}
+
+func TestLintCommitMessage_violations(t *testing.T) {
+	rules := &LintRules{
+		Types:           []string{"feat", "fix"},
+		Scopes:          []string{"api"},
+		SubjectCase:     []string{"sentence-case"},
+		SubjectCaseNot:  true,
+		HeaderMaxLength: 20,
+	}
+	got := lintRuleIDs(LintCommitMessage("chore(svc): Bump everything to latest", rules))
+	want := []string{"header-max-length", "type-enum", "scope-enum", "subject-case"}
+	if len(got) != len(want) {
+		t.Fatalf("LintCommitMessage() rules = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("LintCommitMessage() rules = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLintCommitMessage_unparsableHeader(t *testing.T) {
+	got := lintRuleIDs(LintCommitMessage("Update stuff", &LintRules{}))
+	if len(got) != 1 || got[0] != "header-format" {
+		t.Fatalf("LintCommitMessage() rules = %v, want [header-format]", got)
+	}
+}
+
+func TestLintCommitMessage_defaultRules(t *testing.T) {
+	msg := "feat(api): add profile endpoint\n\nMobile client needs profile data without the full user payload.\n\nRefs #128\nCo-authored-by: A <a@example.com>"
+	if got := LintCommitMessage(msg, DefaultLintRules(72, 72)); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+}
+
+func TestLintCommitMessage_bodyRules(t *testing.T) {
+	cases := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{name: "full stop", msg: "fix: handle empty body.", want: "subject-full-stop"},
+		{name: "leading blank", msg: "fix: handle empty body\nmore", want: "body-leading-blank"},
+		{
+			name: "line length",
+			msg:  "fix: handle empty body\n\nthis body line is much too long to fit the seventy-two character wrapping limit",
+			want: "body-max-line-length",
+		},
+		{name: "trailer", msg: "fix: handle empty body\n\nRefs #12\nCloses 13", want: "trailer-format"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := lintRuleIDs(LintCommitMessage(tc.msg, DefaultLintRules(72, 72)))
+			if len(got) != 1 || got[0] != tc.want {
+				t.Fatalf("LintCommitMessage() rules = %v, want [%s]", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLintCommitMessage_longURLExempt(t *testing.T) {
+	msg := "docs: link spec\n\nSee https://example.com/a/very/long/path/that/cannot/be/wrapped/anywhere/at/all/really"
+	if got := LintCommitMessage(msg, DefaultLintRules(72, 72)); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+}
+
+func TestLintCommitMessage_proseFooterIsNotTrailerBlock(t *testing.T) {
+	msg := "fix: handle empty body\n\nNote: the parser used to panic\nwhen the request had no body."
+	if got := LintCommitMessage(msg, DefaultLintRules(72, 72)); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+}
+
+func TestLintRules_Merge(t *testing.T) {
+	merged := DefaultLintRules(72, 72).Merge(&LintRules{
+		Scopes:          []string{"api"},
+		HeaderMaxLength: 100,
+		Source:          ".commitlintrc",
+	})
+	if merged.HeaderMaxLength != 72 {
+		t.Fatalf("HeaderMaxLength = %d, want stricter 72", merged.HeaderMaxLength)
+	}
+	if len(merged.Types) != len(DefaultCommitTypes) || len(merged.Scopes) != 1 {
+		t.Fatalf("Merge() = %+v", merged)
+	}
+	if merged.Source != ".commitlintrc" {
+		t.Fatalf("Source = %q", merged.Source)
+	}
+}
+
+func TestMatchesCase(t *testing.T) {
+	cases := []struct {
+		subject string
+		name    string
+		want    bool
+	}{
+		{subject: "add thing", name: "lower-case", want: true},
+		{subject: "Add thing", name: "lower-case", want: false},
+		{subject: "Add thing", name: "sentence-case", want: true},
+		{subject: "Add Thing", name: "start-case", want: true},
+		{subject: "ADD THING", name: "upper-case", want: true},
+		{subject: "AddThing", name: "pascal-case", want: true},
+		{subject: "42 tests", name: "upper-case", want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name+"/"+tc.subject, func(t *testing.T) {
+			if got := matchesCase(tc.subject, tc.name); got != tc.want {
+				t.Fatalf("matchesCase(%q, %q) = %v, want %v", tc.subject, tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCleanCommitMessage(t *testing.T) {
+	msg := "fix: handle empty body  \n# Please enter the commit message\n\nDetails.\n" + scissorsLine + "\ndiff --git a/x b/x\n"
+	got := CleanCommitMessage(msg)
+	want := "fix: handle empty body\n\nDetails."
+	if got != want {
+		t.Fatalf("CleanCommitMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestIsIgnoredCommitMessage(t *testing.T) {
+	for _, msg := range []string{"Merge branch 'main'", "Revert \"feat: x\"", "fixup! feat: x"} {
+		if !IsIgnoredCommitMessage(msg) {
+			t.Fatalf("IsIgnoredCommitMessage(%q) = false, want true", msg)
+		}
+	}
+	if IsIgnoredCommitMessage("feat: merge configs") {
+		t.Fatal("IsIgnoredCommitMessage() = true for a regular message")
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppendTrailers adds trailers to the message's trailer block, starting one if
+// the final paragraph isn't made of trailers. Like `git interpret-trailers
+// --if-exists addIfDifferent`, a trailer already present with the same key
+// (case-insensitively) and value is not added again. Entries that aren't
+// "Token: value" or "Token #value" trailers are skipped.
+func AppendTrailers(message string, trailers []string) string {
+	message = strings.TrimRight(message, "\n")
+	body, block := splitTrailerBlock(message)
+
+	existing := make(map[string]bool)
+	for _, line := range block {
+		existing[trailerKey(line)] = true
+	}
+
+	added := false
+	for _, trailer := range trailers {
+		trailer = strings.TrimSpace(trailer)
+		if !trailerPattern.MatchString(trailer) || strings.ContainsAny(trailer, "\r\n") || existing[trailerKey(trailer)] {
+			continue
+		}
+		existing[trailerKey(trailer)] = true
+		block = append(block, trailer)
+		added = true
+	}
+	if !added {
+		return message
+	}
+
+	if body == "" {
+		return strings.Join(block, "\n")
+	}
+	return body + "\n\n" + strings.Join(block, "\n")
+}
+
+// MessageTrailers returns the trailers of message's final trailer block, with
+// folded values unfolded onto one line
+func MessageTrailers(message string) []string {
+	_, block := splitTrailerBlock(strings.TrimRight(message, "\n"))
+	var trailers []string
+	for _, line := range block {
+		if len(trailers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			trailers[len(trailers)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		trailers = append(trailers, line)
+	}
+	return trailers
+}
+
+// splitTrailerBlock separates the final paragraph when all of its lines are
+// trailers or their indented continuations. The subject is never a trailer block.
+func splitTrailerBlock(message string) (string, []string) {
+	i := strings.LastIndex(message, "\n\n")
+	if i < 0 {
+		return message, nil
+	}
+
+	lines := strings.Split(message[i+2:], "\n")
+	for j, line := range lines {
+		continuation := j > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"))
+		if !continuation && !trailerPattern.MatchString(line) {
+			return message, nil
+		}
+	}
+	return message[:i], lines
+}
+
+// trailerKey normalises a trailer for duplicate detection
+func trailerKey(trailer string) string {
+	for _, sep := range []string{": ", " #"} {
+		if key, value, ok := strings.Cut(trailer, sep); ok {
+			return strings.ToLower(key) + sep + strings.TrimSpace(value)
+		}
+	}
+	return trailer
+}
+
+// CoAuthorTrailers turns --co-author values into Co-authored-by trailers. A value
+// is either an alias from the [coauthors] table or a literal "Name <email>".
+func CoAuthorTrailers(coAuthors []string, aliases map[string]string) ([]string, error) {
+	trailers := make([]string, 0, len(coAuthors))
+	for _, coAuthor := range coAuthors {
+		coAuthor = strings.TrimSpace(coAuthor)
+		if identity, ok := aliases[coAuthor]; ok {
+			coAuthor = identity
+		}
+		if !strings.Contains(coAuthor, "<") || !strings.HasSuffix(coAuthor, ">") {
+			return nil, fmt.Errorf("unknown co-author %q, expected an alias from [coauthors] or \"Name <email>\"", coAuthor)
+		}
+		trailers = append(trailers, "Co-authored-by: "+coAuthor)
+	}
+	return trailers, nil
+}
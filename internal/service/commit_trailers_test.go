@@ -0,0 +1,118 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAppendTrailers(t *testing.T) {
+	cases := []struct {
+		name     string
+		message  string
+		trailers []string
+		want     string
+	}{
+		{
+			name:     "starts a block",
+			message:  "feat: add login\n\nUses the new session store.",
+			trailers: []string{"Signed-off-by: A <a@example.com>"},
+			want:     "feat: add login\n\nUses the new session store.\n\nSigned-off-by: A <a@example.com>",
+		},
+		{
+			name:     "subject only",
+			message:  "fix: typo",
+			trailers: []string{"Signed-off-by: A <a@example.com>"},
+			want:     "fix: typo\n\nSigned-off-by: A <a@example.com>",
+		},
+		{
+			name:     "joins the issue footer",
+			message:  "feat: add login\n\nRefs #12",
+			trailers: []string{"Co-authored-by: B <b@example.com>", "Signed-off-by: A <a@example.com>"},
+			want:     "feat: add login\n\nRefs #12\nCo-authored-by: B <b@example.com>\nSigned-off-by: A <a@example.com>",
+		},
+		{
+			name:     "skips entries that aren't trailers",
+			message:  "fix: typo",
+			trailers: []string{"Reviewed-by:", "Jane", "<j@x>", "Reviewed-by: Jane <j@x>"},
+			want:     "fix: typo\n\nReviewed-by: Jane <j@x>",
+		},
+		{
+			name:     "skips duplicates",
+			message:  "feat: add login\n\nsigned-off-by: A <a@example.com>",
+			trailers: []string{"Signed-off-by: A <a@example.com>", "Signed-off-by: A <a@example.com>"},
+			want:     "feat: add login\n\nsigned-off-by: A <a@example.com>",
+		},
+		{
+			name:     "keeps different values",
+			message:  "feat: add login\n\nSigned-off-by: A <a@example.com>",
+			trailers: []string{"Signed-off-by: B <b@example.com>"},
+			want:     "feat: add login\n\nSigned-off-by: A <a@example.com>\nSigned-off-by: B <b@example.com>",
+		},
+		{
+			name:     "breaking change with continuation is a block",
+			message:  "feat!: drop v1\n\nBREAKING CHANGE: v1 endpoints are gone,\n  use v2",
+			trailers: []string{"Signed-off-by: A <a@example.com>"},
+			want:     "feat!: drop v1\n\nBREAKING CHANGE: v1 endpoints are gone,\n  use v2\nSigned-off-by: A <a@example.com>",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := AppendTrailers(tc.message, tc.trailers); got != tc.want {
+				t.Fatalf("AppendTrailers() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCoAuthorTrailers(t *testing.T) {
+	aliases := map[string]string{"jane": "Jane Doe <jane@example.com>"}
+	got, err := CoAuthorTrailers([]string{"jane", "Sam Lee <sam@example.com>"}, aliases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "Co-authored-by: Jane Doe <jane@example.com>" || got[1] != "Co-authored-by: Sam Lee <sam@example.com>" {
+		t.Fatalf("CoAuthorTrailers() = %v", got)
+	}
+
+	if _, err := CoAuthorTrailers([]string{"bob"}, aliases); err == nil {
+		t.Fatal("CoAuthorTrailers() with an unknown alias returned no error")
+	}
+}
+
+func TestMessageTrailers(t *testing.T) {
+	message := "wip\n\nSome body.\n\nRefs #12\nCo-authored-by: Jane Doe\n <jane@example.com>\nSigned-off-by: Sam <sam@example.com>"
+	got := MessageTrailers(message)
+	want := []string{"Refs #12", "Co-authored-by: Jane Doe <jane@example.com>", "Signed-off-by: Sam <sam@example.com>"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("MessageTrailers() = %q, want %q", got, want)
+	}
+
+	if got := MessageTrailers("wip\n\nJust a body."); len(got) != 0 {
+		t.Fatalf("MessageTrailers() without trailers = %q", got)
+	}
+
+	// Kept trailers are not duplicated when the same ones are added again
+	amended := AppendTrailers("fix: handle empty body", append(want, "Signed-off-by: Sam <sam@example.com>"))
+	if strings.Count(amended, "Signed-off-by") != 1 {
+		t.Fatalf("AppendTrailers() = %q", amended)
+	}
+}
+
+func TestIssueFooterWithSignoff(t *testing.T) {
+	issues := []IssueRef{{ID: "PROJ-123"}, {ID: "34", Keyword: "Closes"}}
+	message := (&IssueConfig{}).AppendIssueRefs("feat: add login", issues, "Refs")
+	message = AppendTrailers(message, []string{"Signed-off-by: A <a@example.com>"})
+
+	want := "feat: add login\n\nRefs: PROJ-123\nCloses #34\nSigned-off-by: A <a@example.com>"
+	if message != want {
+		t.Fatalf("message = %q, want %q", message, want)
+	}
+	if violations := LintCommitMessage(message, DefaultLintRules(72, 100)); len(violations) != 0 {
+		t.Fatalf("LintCommitMessage() = %v", violations)
+	}
+	// amend and reword keep the issue references along with the sign-off
+	if got := MessageTrailers(message); len(got) != 3 {
+		t.Fatalf("MessageTrailers() = %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+
+// WrapCommitBody hard-wraps body paragraphs that have lines longer than width.
+// The header, the trailer block, code blocks and words that can't be split (such
+// as URLs) are left untouched; list items are wrapped with a hanging indent.
+func WrapCommitBody(message string, width int) string {
+	if width <= 0 {
+		return message
+	}
+
+	header, body, found := strings.Cut(message, "\n")
+	if !found || strings.TrimSpace(body) == "" {
+		return message
+	}
+
+	paragraphs := strings.Split(strings.Trim(body, "\n"), "\n\n")
+	for i, paragraph := range paragraphs {
+		if !needsWrap(paragraph, width) || isCodeBlock(paragraph) {
+			continue
+		}
+		if i == len(paragraphs)-1 && isTrailerBlock(paragraph) {
+			continue
+		}
+		paragraphs[i] = wrapParagraph(paragraph, width)
+	}
+
+	return header + "\n\n" + strings.Join(paragraphs, "\n\n")
+}
+
+func needsWrap(paragraph string, width int) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if utf8.RuneCountInString(line) > width {
+			return true
+		}
+	}
+	return false
+}
+
+func isCodeBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapParagraph re-flows a paragraph, keeping every list item as its own block
+func wrapParagraph(paragraph string, width int) string {
+	var wrapped []string
+	var prefix, indent string
+	var words []string
+
+	flush := func() {
+		if len(words) > 0 {
+			wrapped = append(wrapped, wrapWords(words, width, prefix, indent)...)
+		}
+		words = nil
+	}
+
+	for _, line := range strings.Split(paragraph, "\n") {
+		if m := listItemPattern.FindString(line); m != "" {
+			flush()
+			prefix = m
+			indent = strings.Repeat(" ", utf8.RuneCountInString(m))
+			words = strings.Fields(line[len(m):])
+			continue
+		}
+		if len(words) == 0 {
+			prefix, indent = "", ""
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+	flush()
+
+	return strings.Join(wrapped, "\n")
+}
+
+// wrapWords greedily fills lines up to width; a word longer than width gets a line of its own
+func wrapWords(words []string, width int, prefix, indent string) []string {
+	var lines []string
+	current := prefix
+	currentLen := utf8.RuneCountInString(prefix)
+	empty := true
+
+	for _, word := range words {
+		wordLen := utf8.RuneCountInString(word)
+		if !empty && currentLen+1+wordLen > width {
+			lines = append(lines, current)
+			current, currentLen, empty = indent, utf8.RuneCountInString(indent), true
+		}
+		if !empty {
+			current += " "
+			currentLen++
+		}
+		current += word
+		currentLen += wordLen
+		empty = false
+	}
+
+	return append(lines, current)
+}
@@ -0,0 +1,51 @@
+package service
+
+import "testing"
+
+func TestWrapCommitBody_paragraph(t *testing.T) {
+	msg := "feat(api): add profile endpoint\n\nMobile client needs profile data without the full user payload to reduce LTE bandwidth on cold-launch screens."
+	got := WrapCommitBody(msg, 40)
+	want := "feat(api): add profile endpoint\n\nMobile client needs profile data without\nthe full user payload to reduce LTE\nbandwidth on cold-launch screens."
+	if got != want {
+		t.Fatalf("WrapCommitBody() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapCommitBody_bulletsKeepHangingIndent(t *testing.T) {
+	msg := "fix: x\n\n- first item that is rather long and needs wrapping\n- second"
+	got := WrapCommitBody(msg, 30)
+	want := "fix: x\n\n- first item that is rather\n  long and needs wrapping\n- second"
+	if got != want {
+		t.Fatalf("WrapCommitBody() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapCommitBody_keepsURLsWhole(t *testing.T) {
+	msg := "docs: link spec\n\nSee https://example.com/a/very/long/path/that/cannot/be/wrapped for details"
+	got := WrapCommitBody(msg, 30)
+	want := "docs: link spec\n\nSee\nhttps://example.com/a/very/long/path/that/cannot/be/wrapped\nfor details"
+	if got != want {
+		t.Fatalf("WrapCommitBody() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapCommitBody_leavesHeaderAndTrailers(t *testing.T) {
+	msg := "feat(api): a header that is longer than the wrap width\n\nCo-authored-by: Someone With A Long Name <someone@example.com>"
+	if got := WrapCommitBody(msg, 30); got != msg {
+		t.Fatalf("WrapCommitBody() = %q, want unchanged", got)
+	}
+}
+
+func TestWrapCommitBody_leavesShortParagraphs(t *testing.T) {
+	msg := "fix: x\n\nline one\nline two"
+	if got := WrapCommitBody(msg, 72); got != msg {
+		t.Fatalf("WrapCommitBody() = %q, want unchanged", got)
+	}
+}
+
+func TestWrapCommitBody_disabled(t *testing.T) {
+	msg := "fix: x\n\na very long line that would otherwise be wrapped somewhere"
+	if got := WrapCommitBody(msg, 0); got != msg {
+		t.Fatalf("WrapCommitBody() = %q, want unchanged", got)
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// commitlintFiles are checked in the same order commitlint's cosmiconfig uses.
+var commitlintFiles = []string{
+	".commitlintrc",
+	".commitlintrc.json",
+	".commitlintrc.yaml",
+	".commitlintrc.yml",
+	".commitlintrc.js",
+	".commitlintrc.cjs",
+	".commitlintrc.mjs",
+	".commitlintrc.ts",
+	".commitlintrc.cts",
+	"commitlint.config.js",
+	"commitlint.config.cjs",
+	"commitlint.config.mjs",
+	"commitlint.config.ts",
+	"commitlint.config.cts",
+}
+
+// supportedCommitlintRules are the rules geminicommit understands. Only the
+// first four take values; the others can be turned off or made warnings.
+var supportedCommitlintRules = []string{
+	"type-enum", "scope-enum", "subject-case", "header-max-length",
+	"scope-empty", "subject-empty", "subject-full-stop", "body-leading-blank", "body-max-line-length",
+}
+
+// conventionalRules mirrors the relevant parts of @commitlint/config-conventional.
+func conventionalRules() *LintRules {
+	return &LintRules{
+		Types: []string{
+			"build", "chore", "ci", "docs", "feat", "fix",
+			"perf", "refactor", "revert", "style", "test",
+		},
+		SubjectCase:     []string{"sentence-case", "start-case", "pascal-case", "upper-case"},
+		SubjectCaseNot:  true,
+		HeaderMaxLength: 100,
+	}
+}
+
+// LoadLintRules returns geminicommit's default rules, tightened by the commitlint
+// configuration in root when there is one.
+func LoadLintRules(root string, subjectMaxLength, bodyWrap int) (*LintRules, error) {
+	rules := DefaultLintRules(subjectMaxLength, bodyWrap)
+	if root == "" {
+		return rules, nil
+	}
+	commitlintRules, err := LoadCommitlintRules(root)
+	return rules.Merge(commitlintRules), err
+}
+
+// LoadCommitlintRules looks for a commitlint configuration in root and extracts the
+// rules geminicommit can enforce. It returns nil when no configuration exists.
+func LoadCommitlintRules(root string) (*LintRules, error) {
+	for _, name := range commitlintFiles {
+		path := filepath.Join(root, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var cfg map[string]any
+		switch filepath.Ext(name) {
+		case ".js", ".cjs", ".mjs", ".ts", ".cts":
+			cfg = scrapeScriptConfig(string(content))
+		default:
+			// YAML is a superset of JSON, so this covers .json and extensionless files too
+			if err := yaml.Unmarshal(content, &cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+			}
+		}
+		return commitlintRulesFromConfig(cfg, name), nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil, nil
+	}
+	var pkg struct {
+		Commitlint map[string]any `json:"commitlint"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %v", err)
+	}
+	if pkg.Commitlint == nil {
+		return nil, nil
+	}
+	return commitlintRulesFromConfig(pkg.Commitlint, "package.json#commitlint"), nil
+}
+
+func commitlintRulesFromConfig(cfg map[string]any, source string) *LintRules {
+	rules := &LintRules{}
+	if extendsConventional(cfg["extends"]) {
+		rules = conventionalRules()
+	}
+	rules.Source = source
+
+	ruleMap, _ := cfg["rules"].(map[string]any)
+	for name, raw := range ruleMap {
+		entry, ok := raw.([]any)
+		if !ok || len(entry) == 0 {
+			continue
+		}
+		level, _ := toInt(entry[0])
+		when := "always"
+		if len(entry) > 1 {
+			if s, ok := entry[1].(string); ok {
+				when = s
+			}
+		}
+		var value any
+		if len(entry) > 2 {
+			value = entry[2]
+		}
+
+		// Level 0 turns the rule off, even where geminicommit has a default of
+		// its own; level 1 only warns
+		switch level {
+		case 0:
+			rules.Disabled = append(rules.Disabled, name)
+		case 1:
+			rules.Warnings = append(rules.Warnings, name)
+		}
+
+		switch name {
+		case "type-enum":
+			rules.Types = nil
+			if level > 0 && when == "always" {
+				rules.Types = toStrings(value)
+			}
+		case "scope-enum":
+			rules.Scopes = nil
+			if level > 0 && when == "always" {
+				rules.Scopes = toStrings(value)
+			}
+		case "subject-case":
+			rules.SubjectCase = nil
+			if level > 0 {
+				rules.SubjectCase = toStrings(value)
+				rules.SubjectCaseNot = when == "never"
+			}
+		case "header-max-length":
+			rules.HeaderMaxLength = 0
+			if level > 0 {
+				rules.HeaderMaxLength, _ = toInt(value)
+			}
+		}
+	}
+
+	return rules
+}
+
+func extendsConventional(extends any) bool {
+	for _, e := range toStrings(extends) {
+		if strings.Contains(e, "config-conventional") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	scriptLineComment = regexp.MustCompile(`(?m)^\s*//.*$`)
+	scriptTrailComma  = regexp.MustCompile(`,(\s*[\]}])`)
+	scriptSeverity    = strings.NewReplacer(
+		"RuleConfigSeverity.Disabled", "0",
+		"RuleConfigSeverity.Warning", "1",
+		"RuleConfigSeverity.Error", "2",
+	)
+)
+
+// scrapeScriptConfig extracts rules from a JS/TS config without executing it.
+// ponytail: only literal rule arrays are understood; computed values are ignored.
+func scrapeScriptConfig(src string) map[string]any {
+	src = scriptLineComment.ReplaceAllString(src, "")
+	src = scriptSeverity.Replace(src)
+
+	rules := map[string]any{}
+	for _, name := range supportedCommitlintRules {
+		key := regexp.MustCompile(`['"]?` + regexp.QuoteMeta(name) + `['"]?\s*:\s*\[`)
+		loc := key.FindStringIndex(src)
+		if loc == nil {
+			continue
+		}
+		literal, ok := balancedBrackets(src[loc[1]-1:])
+		if !ok {
+			continue
+		}
+		literal = scriptTrailComma.ReplaceAllString(literal, "$1")
+
+		// JS array literals with quoted strings are valid YAML flow sequences
+		var entry []any
+		if err := yaml.Unmarshal([]byte(literal), &entry); err == nil {
+			rules[name] = entry
+		}
+	}
+
+	cfg := map[string]any{"rules": rules}
+	if strings.Contains(src, "config-conventional") {
+		cfg["extends"] = "@commitlint/config-conventional"
+	}
+	return cfg
+}
+
+// balancedBrackets returns the prefix of s up to the bracket closing s[0].
+func balancedBrackets(s string) (string, bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1], true
+			}
+		}
+	}
+	return "", false
+}
+
+func toStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && !slices.Contains(out, s) {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func toInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
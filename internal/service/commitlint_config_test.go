@@ -0,0 +1,197 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCommitlintRules_none(t *testing.T) {
+	rules, err := LoadCommitlintRules(t.TempDir())
+	if err != nil || rules != nil {
+		t.Fatalf("LoadCommitlintRules() = %v, %v, want nil, nil", rules, err)
+	}
+}
+
+func TestLoadCommitlintRules_json(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".commitlintrc.json", `{
+  "rules": {
+    "type-enum": [2, "always", ["feat", "fix"]],
+    "scope-enum": [2, "always", ["api", "cli"]],
+    "header-max-length": [2, "always", 60]
+  }
+}`)
+
+	rules, err := LoadCommitlintRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rules.Types, []string{"feat", "fix"}) {
+		t.Fatalf("Types = %v", rules.Types)
+	}
+	if !slices.Equal(rules.Scopes, []string{"api", "cli"}) {
+		t.Fatalf("Scopes = %v", rules.Scopes)
+	}
+	if rules.HeaderMaxLength != 60 {
+		t.Fatalf("HeaderMaxLength = %d, want 60", rules.HeaderMaxLength)
+	}
+}
+
+func TestLoadCommitlintRules_yamlExtendsConventional(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".commitlintrc.yml", `extends:
+  - "@commitlint/config-conventional"
+rules:
+  header-max-length: [0, always, 100]
+`)
+
+	rules, err := LoadCommitlintRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(rules.Types, "refactor") {
+		t.Fatalf("Types = %v, want conventional types", rules.Types)
+	}
+	if rules.HeaderMaxLength != 0 {
+		t.Fatalf("HeaderMaxLength = %d, want disabled", rules.HeaderMaxLength)
+	}
+	if !rules.SubjectCaseNot {
+		t.Fatal("SubjectCaseNot = false, want true")
+	}
+}
+
+func TestLoadCommitlintRules_script(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "commitlint.config.ts", `import { RuleConfigSeverity } from '@commitlint/types';
+
+export default {
+  extends: ['@commitlint/config-conventional'],
+  rules: {
+    // keep scopes in sync with the workspace
+    'scope-enum': [RuleConfigSeverity.Error, 'always', ['web', 'server',]],
+    'subject-case': [2, 'always', 'lower-case'],
+  },
+};
+`)
+
+	rules, err := LoadCommitlintRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rules.Scopes, []string{"web", "server"}) {
+		t.Fatalf("Scopes = %v", rules.Scopes)
+	}
+	if !slices.Equal(rules.SubjectCase, []string{"lower-case"}) || rules.SubjectCaseNot {
+		t.Fatalf("SubjectCase = %v (not=%v)", rules.SubjectCase, rules.SubjectCaseNot)
+	}
+	if rules.Source != "commitlint.config.ts" {
+		t.Fatalf("Source = %q", rules.Source)
+	}
+}
+
+func TestLoadCommitlintRules_packageJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "package.json", `{
+  "name": "web",
+  "commitlint": {"rules": {"type-enum": [2, "always", ["feat"]]}}
+}`)
+
+	rules, err := LoadCommitlintRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rules.Types, []string{"feat"}) {
+		t.Fatalf("Types = %v", rules.Types)
+	}
+}
+
+func TestLoadCommitlintRules_packageJSONWithoutCommitlint(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "package.json", `{"name": "web"}`)
+
+	rules, err := LoadCommitlintRules(dir)
+	if err != nil || rules != nil {
+		t.Fatalf("LoadCommitlintRules() = %v, %v, want nil, nil", rules, err)
+	}
+}
+
+func TestLoadLintRules_disabledLevel(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".commitlintrc.yml", `extends:
+  - "@commitlint/config-conventional"
+rules:
+  type-enum: [0, always, [feat, fix]]
+  scope-enum: [0]
+`)
+
+	rules, err := LoadLintRules(dir, 72, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Types) != 0 || len(rules.Scopes) != 0 {
+		t.Fatalf("Types = %v, Scopes = %v, want both disabled", rules.Types, rules.Scopes)
+	}
+	// No fallback to geminicommit's built-in types
+	if got := LintCommitMessage("wip(anything): save progress", rules); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+}
+
+func TestLoadLintRules_disabledBodyRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".commitlintrc.yml", `rules:
+  body-max-line-length: [0]
+  subject-full-stop: [0, never, "."]
+`)
+
+	rules, err := LoadLintRules(dir, 72, 72)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := "feat: add login.\n\n" + strings.Repeat("word ", 30)
+	if got := LintCommitMessage(msg, rules); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want none", got)
+	}
+	// Without the config both rules apply
+	if got := lintRuleIDs(LintCommitMessage(msg, DefaultLintRules(72, 72))); len(got) != 2 {
+		t.Fatalf("LintCommitMessage() rules = %v, want subject-full-stop and body-max-line-length", got)
+	}
+}
+
+func TestLoadLintRules_warningLevel(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".commitlintrc.json", `{
+  "rules": {
+    "type-enum": [1, "always", ["feat", "fix"]],
+    "header-max-length": [1, "always", 100]
+  }
+}`)
+
+	rules, err := LoadLintRules(dir, 50, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations := LintCommitMessage("chore: update the build scripts", rules)
+	if len(violations) != 1 || violations[0].Rule != "type-enum" || !violations[0].Warning {
+		t.Fatalf("LintCommitMessage() = %v, want a type-enum warning", violations)
+	}
+	if errors := LintErrors(violations); len(errors) != 0 {
+		t.Fatalf("LintErrors() = %v, want none", errors)
+	}
+
+	// geminicommit's own stricter header limit is still an error
+	violations = LintCommitMessage("feat: "+strings.Repeat("x", 60), rules)
+	if errors := LintErrors(violations); len(errors) != 1 || errors[0].Rule != "header-max-length" {
+		t.Fatalf("LintErrors() = %v, want header-max-length", errors)
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 var (
-	DefaultModel   = "gemini-3.5-flash"
-	DefaultBaseUrl = ""
+	DefaultModel            = "gemini-3.5-flash"
+	DefaultBaseUrl          = ""
+	DefaultSubjectMaxLength = 72
+	DefaultBodyWrap         = 72
 )
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// lockfiles are regenerated by package managers; their diff is never worth sending
+var lockfiles = map[string]bool{
+	"go.sum": true, "go.work.sum": true,
+	"package-lock.json": true, "npm-shrinkwrap.json": true, "yarn.lock": true,
+	"pnpm-lock.yaml": true, "bun.lock": true, "bun.lockb": true,
+	"Cargo.lock": true, "poetry.lock": true, "Pipfile.lock": true, "uv.lock": true,
+	"composer.lock": true, "Gemfile.lock": true,
+}
+
+// DependencyChange is one dependency added (From empty), removed (To empty) or bumped
+type DependencyChange struct {
+	Name string
+	From string
+	To   string
+}
+
+func (c DependencyChange) String() string {
+	switch {
+	case c.From == "":
+		return strings.TrimSpace("add " + c.Name + " " + c.To)
+	case c.To == "":
+		return "remove " + c.Name
+	default:
+		return fmt.Sprintf("bump %s from %s to %s", c.Name, c.From, c.To)
+	}
+}
+
+// ManifestChanges summarises how one dependency manifest or lockfile changed
+type ManifestChanges struct {
+	File     string
+	Lockfile bool
+	Changes  []DependencyChange
+	// OtherChanges is set when the manifest also changed outside its dependency lists
+	OtherChanges bool
+}
+
+// isManifest reports whether path is a dependency manifest geminicommit can parse
+func isManifest(path string) bool {
+	name := filepath.Base(path)
+	switch name {
+	case "go.mod", "package.json", "Cargo.toml":
+		return true
+	}
+	return strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt")
+}
+
+// isLockfile reports whether path is a package manager lockfile
+func isLockfile(path string) bool {
+	return lockfiles[filepath.Base(path)]
+}
+
+// DiffDependencies compares the dependencies declared by two versions of a
+// manifest; oldSrc is nil for new files and newSrc nil for deleted ones
+func DiffDependencies(path string, oldSrc, newSrc []byte) (ManifestChanges, error) {
+	changes := ManifestChanges{File: path}
+
+	oldDeps, oldRest, err := parseDependencies(path, oldSrc)
+	if err != nil {
+		return changes, err
+	}
+	newDeps, newRest, err := parseDependencies(path, newSrc)
+	if err != nil {
+		return changes, err
+	}
+
+	for name, to := range newDeps {
+		from, ok := oldDeps[name]
+		switch {
+		case !ok:
+			changes.Changes = append(changes.Changes, DependencyChange{Name: name, To: to})
+		case from != to:
+			changes.Changes = append(changes.Changes, DependencyChange{Name: name, From: from, To: to})
+		}
+	}
+	for name, from := range oldDeps {
+		if _, ok := newDeps[name]; !ok {
+			changes.Changes = append(changes.Changes, DependencyChange{Name: name, From: from})
+		}
+	}
+	sort.Slice(changes.Changes, func(i, j int) bool { return changes.Changes[i].Name < changes.Changes[j].Name })
+	changes.OtherChanges = oldRest != newRest
+
+	return changes, nil
+}
+
+// parseDependencies returns the dependencies a manifest declares and a
+// normalised rendering of everything else in it
+func parseDependencies(path string, src []byte) (map[string]string, string, error) {
+	if src == nil {
+		return map[string]string{}, "", nil
+	}
+
+	switch name := filepath.Base(path); {
+	case name == "go.mod":
+		deps, rest := goModDependencies(src)
+		return deps, rest, nil
+	case name == "package.json":
+		return packageJSONDependencies(src)
+	case name == "Cargo.toml":
+		return cargoDependencies(src)
+	default:
+		deps, rest := requirementsDependencies(src)
+		return deps, rest, nil
+	}
+}
+
+func goModDependencies(src []byte) (map[string]string, string) {
+	deps := map[string]string{}
+	var rest []string
+	inRequire := false
+
+	scanner := bufio.NewScanner(strings.NewReader(string(src)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire && len(fields) >= 2:
+			deps[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) >= 3:
+			deps[fields[1]] = fields[2]
+		case (fields[0] == "go" || fields[0] == "toolchain") && len(fields) == 2:
+			deps[fields[0]] = fields[1]
+		default:
+			rest = append(rest, strings.Join(fields, " "))
+		}
+	}
+
+	return deps, strings.Join(rest, "\n")
+}
+
+var packageJSONSections = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+func packageJSONDependencies(src []byte) (map[string]string, string, error) {
+	var manifest map[string]any
+	if err := json.Unmarshal(src, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse package.json: %v", err)
+	}
+
+	deps := map[string]string{}
+	for _, section := range packageJSONSections {
+		entries, _ := manifest[section].(map[string]any)
+		for name, version := range entries {
+			deps[name] = fmt.Sprint(version)
+		}
+		delete(manifest, section)
+	}
+
+	rest, _ := json.Marshal(manifest)
+	return deps, string(rest), nil
+}
+
+var cargoSections = []string{"dependencies", "dev-dependencies", "build-dependencies"}
+
+func cargoDependencies(src []byte) (map[string]string, string, error) {
+	var manifest map[string]any
+	if err := toml.Unmarshal(src, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse Cargo.toml: %v", err)
+	}
+
+	deps := map[string]string{}
+	collect := func(table map[string]any) {
+		for _, section := range cargoSections {
+			entries, _ := table[section].(map[string]any)
+			for name, spec := range entries {
+				deps[name] = cargoVersion(spec)
+			}
+			delete(table, section)
+		}
+	}
+	collect(manifest)
+	if workspace, ok := manifest["workspace"].(map[string]any); ok {
+		collect(workspace)
+	}
+
+	rest, _ := json.Marshal(manifest)
+	return deps, string(rest), nil
+}
+
+// cargoVersion reads `foo = "1.0"` as well as `foo = { version = "1.0" }` and git/path dependencies
+func cargoVersion(spec any) string {
+	table, ok := spec.(map[string]any)
+	if !ok {
+		return fmt.Sprint(spec)
+	}
+	for _, key := range []string{"version", "rev", "tag", "branch", "git", "path"} {
+		if v, ok := table[key]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+func requirementsDependencies(src []byte) (map[string]string, string) {
+	deps := map[string]string{}
+	var rest []string
+
+	scanner := bufio.NewScanner(strings.NewReader(string(src)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			// Options such as -r other.txt or --index-url
+			rest = append(rest, line)
+			continue
+		}
+
+		end := strings.IndexAny(line, "=<>!~;[ @")
+		if end < 0 {
+			deps[strings.ToLower(line)] = ""
+			continue
+		}
+		spec := strings.TrimSpace(line[end:])
+		deps[strings.ToLower(line[:end])] = strings.TrimPrefix(spec, "==")
+	}
+
+	return deps, strings.Join(rest, "\n")
+}
+
+// FormatDependencyChanges renders the dependency changes for the prompt
+func FormatDependencyChanges(manifests []ManifestChanges) string {
+	var lines []string
+	for _, m := range manifests {
+		switch {
+		case m.Lockfile:
+			lines = append(lines, m.File+": lockfile updated")
+		case len(m.Changes) > 0:
+			descriptions := make([]string, 0, len(m.Changes))
+			for _, c := range m.Changes {
+				descriptions = append(descriptions, c.String())
+			}
+			lines = append(lines, m.File+": "+strings.Join(descriptions, ", "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripDependencyDiffs replaces the diff of lockfiles, and of manifests whose
+// only change is to their dependencies, with a pointer to the dependency summary
+func stripDependencyDiffs(diff string, manifests []ManifestChanges) string {
+	summarised := map[string]bool{}
+	for _, m := range manifests {
+		if m.Lockfile || (len(m.Changes) > 0 && !m.OtherChanges) {
+			summarised[m.File] = true
+		}
+	}
+	if len(summarised) == 0 {
+		return diff
+	}
+
+	files := splitDiff(diff)
+	for i, f := range files {
+		if summarised[f.Path] {
+			files[i].Text = fileStub(f.Path, "(summarised under dependency changes)")
+		}
+	}
+	return joinFileDiffs(files)
+}
+
+// DependencyCommitMessage writes a build(deps) message for a commit that only
+// changes dependencies, without asking the model. It returns "" when the
+// manifests changed in other ways too or nothing was bumped, added or removed.
+func DependencyCommitMessage(manifests []ManifestChanges, subjectMaxLength int) string {
+	var changes []DependencyChange
+	seen := map[DependencyChange]bool{}
+	for _, m := range manifests {
+		if m.OtherChanges {
+			return ""
+		}
+		for _, c := range m.Changes {
+			if !seen[c] {
+				seen[c] = true
+				changes = append(changes, c)
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	const prefix = "build(deps): "
+	if len(changes) == 1 {
+		if subject := prefix + changes[0].String(); subjectMaxLength <= 0 || len(subject) <= subjectMaxLength {
+			return subject
+		}
+	}
+
+	// Too many or too long to fit the subject: name them there and detail them in the body
+
+	verb := "bump"
+	names := make([]string, 0, len(changes))
+	for _, c := range changes {
+		if c.From == "" || c.To == "" {
+			verb = "update"
+		}
+		names = append(names, c.Name)
+	}
+
+	subject := prefix + verb + " " + joinNames(names)
+	if subjectMaxLength > 0 && len(subject) > subjectMaxLength {
+		noun := "dependencies"
+		if len(changes) == 1 {
+			noun = "dependency"
+		}
+		subject = fmt.Sprintf("%s%s %d %s", prefix, verb, len(changes), noun)
+	}
+
+	body := make([]string, 0, len(changes))
+	for _, c := range changes {
+		body = append(body, "- "+c.String())
+	}
+	return subject + "\n\n" + strings.Join(body, "\n")
+}
+
+// joinNames lists names as "a, b and c"
+func joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffDependenciesGoMod(t *testing.T) {
+	oldSrc := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/a/one v1.2.0
+	github.com/b/two v0.3.0 // indirect
+)
+`
+	newSrc := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/a/one v1.3.0
+	github.com/c/three v2.0.0
+)
+`
+
+	got, err := DiffDependencies("go.mod", []byte(oldSrc), []byte(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DependencyChange{
+		{Name: "github.com/a/one", From: "v1.2.0", To: "v1.3.0"},
+		{Name: "github.com/b/two", From: "v0.3.0"},
+		{Name: "github.com/c/three", To: "v2.0.0"},
+	}
+	if !slices.Equal(got.Changes, want) {
+		t.Fatalf("Changes = %v", got.Changes)
+	}
+	if got.OtherChanges {
+		t.Fatal("OtherChanges = true, want false")
+	}
+}
+
+func TestDiffDependenciesOtherChanges(t *testing.T) {
+	oldSrc := `{"name": "app", "dependencies": {"left-pad": "^1.0.0"}}`
+	newSrc := `{"name": "app", "scripts": {"build": "tsc"}, "dependencies": {"left-pad": "^1.1.0"}}`
+
+	got, err := DiffDependencies("web/package.json", []byte(oldSrc), []byte(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Changes) != 1 || got.Changes[0].String() != "bump left-pad from ^1.0.0 to ^1.1.0" {
+		t.Fatalf("Changes = %v", got.Changes)
+	}
+	if !got.OtherChanges {
+		t.Fatal("OtherChanges = false, want true")
+	}
+}
+
+func TestDiffDependenciesCargoAndRequirements(t *testing.T) {
+	oldCargo := `[package]
+name = "app"
+
+[dependencies]
+serde = "1.0.100"
+`
+	newCargo := `[package]
+name = "app"
+
+[dependencies]
+serde = { version = "1.0.200", features = ["derive"] }
+`
+	got, err := DiffDependencies("Cargo.toml", []byte(oldCargo), []byte(newCargo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Changes, []DependencyChange{{Name: "serde", From: "1.0.100", To: "1.0.200"}}) {
+		t.Fatalf("Cargo Changes = %v", got.Changes)
+	}
+
+	got, err = DiffDependencies("requirements.txt", []byte("Django==4.2\nrequests>=2\n"), []byte("django==5.0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DependencyChange{{Name: "django", From: "4.2", To: "5.0"}, {Name: "requests", From: ">=2"}}
+	if !slices.Equal(got.Changes, want) {
+		t.Fatalf("requirements Changes = %v", got.Changes)
+	}
+}
+
+func TestDependencyCommitMessage(t *testing.T) {
+	bump := []ManifestChanges{
+		{File: "go.mod", Changes: []DependencyChange{{Name: "github.com/a/one", From: "v1.2.0", To: "v1.3.0"}}},
+		{File: "go.sum", Lockfile: true},
+	}
+	if got, want := DependencyCommitMessage(bump, 72), "build(deps): bump github.com/a/one from v1.2.0 to v1.3.0"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	several := []ManifestChanges{{File: "package.json", Changes: []DependencyChange{
+		{Name: "react", From: "18.2.0", To: "18.3.0"},
+		{Name: "vite", To: "5.0.0"},
+	}}}
+	want := "build(deps): update react and vite\n\n- bump react from 18.2.0 to 18.3.0\n- add vite 5.0.0"
+	if got := DependencyCommitMessage(several, 72); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := DependencyCommitMessage(several, 20); !strings.HasPrefix(got, "build(deps): update 2 dependencies\n\n") {
+		t.Fatalf("got %q", got)
+	}
+
+	other := []ManifestChanges{{File: "go.mod", OtherChanges: true, Changes: bump[0].Changes}}
+	if got := DependencyCommitMessage(other, 72); got != "" {
+		t.Fatalf("got %q, want empty", got)
+	}
+}
+
+func TestStripDependencyDiffs(t *testing.T) {
+	diff := "diff --git a/go.sum b/go.sum\n--- a/go.sum\n+++ b/go.sum\n@@ -1 +1 @@\n-h1:old\n+h1:new\n" +
+		"diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	got := stripDependencyDiffs(diff, []ManifestChanges{{File: "go.sum", Lockfile: true}})
+	if !strings.Contains(got, "(summarised under dependency changes)") || !strings.Contains(got, "+b") || strings.Contains(got, "h1:new") {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Rename detection modes for DiffOptions.Renames
+const (
+	DiffRenames   = "renames"
+	DiffCopies    = "copies"
+	DiffNoRenames = "off"
+)
+
+// DefaultDiffAlgorithm and DefaultDiffContextLines match what geminicommit always used
+const (
+	DefaultDiffAlgorithm    = "minimal"
+	DefaultDiffContextLines = 3
+)
+
+var diffAlgorithms = []string{"myers", "minimal", "patience", "histogram"}
+
+// gmcignoreFile lists repository-wide diff exclusions, one gitignore-style pattern per line
+const gmcignoreFile = ".gmcignore"
+
+// DiffOptions controls how diffs are produced for the prompt
+type DiffOptions struct {
+	// Exclude holds gitignore-style patterns; matching files stay in the file
+	// list but their diff is replaced by a one-line stub.
+	Exclude []string
+	// MaxFileKB is the size above which new files are summarised instead of
+	// diffed; zero means DefaultMaxFileKB and a negative value disables the limit.
+	MaxFileKB int
+	// Algorithm is passed to --diff-algorithm; empty means DefaultDiffAlgorithm
+	Algorithm string
+	// ContextLines is the number of unchanged lines around each hunk (-U)
+	ContextLines int
+	// FunctionContext shows the whole enclosing function of each change (-W)
+	FunctionContext bool
+	// IgnoreWhitespace drops whitespace-only changes (-w)
+	IgnoreWhitespace bool
+	// FullFilesKB is the budget for sending the whole post-change content of
+	// small modified files alongside the diff; zero disables it.
+	FullFilesKB int
+	// Renames is DiffRenames (-M), DiffCopies (-C) or DiffNoRenames; empty means DiffRenames
+	Renames string
+}
+
+// Validate reports unknown algorithms, rename modes and negative context sizes
+func (o *DiffOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Algorithm != "" && !slices.Contains(diffAlgorithms, o.Algorithm) {
+		return fmt.Errorf("invalid diff algorithm %q, expected one of %s", o.Algorithm, strings.Join(diffAlgorithms, ", "))
+	}
+	switch o.Renames {
+	case "", DiffRenames, DiffCopies, DiffNoRenames:
+	default:
+		return fmt.Errorf("invalid rename detection %q, expected renames, copies or off", o.Renames)
+	}
+	if o.FullFilesKB < 0 {
+		return fmt.Errorf("invalid full files budget %d, expected 0 or more", o.FullFilesKB)
+	}
+	if o.ContextLines < 0 {
+		return fmt.Errorf("invalid diff context lines %d, expected 0 or more", o.ContextLines)
+	}
+	return nil
+}
+
+// gitArgs returns the git diff flags for the configured algorithm, context and rename detection
+func (o *DiffOptions) gitArgs() []string {
+	if o == nil {
+		return []string{"--diff-algorithm=" + DefaultDiffAlgorithm}
+	}
+
+	algorithm := o.Algorithm
+	if algorithm == "" {
+		algorithm = DefaultDiffAlgorithm
+	}
+	args := []string{"--diff-algorithm=" + algorithm, fmt.Sprintf("-U%d", o.ContextLines)}
+	if o.FunctionContext {
+		args = append(args, "--function-context")
+	}
+	if o.IgnoreWhitespace {
+		args = append(args, "--ignore-all-space")
+	}
+	switch o.Renames {
+	case DiffCopies:
+		args = append(args, "--find-copies")
+	case DiffNoRenames:
+		args = append(args, "--no-renames")
+	default:
+		args = append(args, "--find-renames")
+	}
+	return args
+}
+
+// LoadDiffExcludes combines the configured patterns with those in root/.gmcignore
+func LoadDiffExcludes(root string, configured []string) []string {
+	patterns := append([]string{}, configured...)
+
+	file, err := os.Open(filepath.Join(root, gmcignoreFile))
+	if err != nil {
+		return patterns
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns
+}
+
+// pathspecs converts gitignore-style patterns to git pathspecs, optionally
+// with the exclude magic. Negated patterns are not supported and skipped.
+func pathspecs(patterns []string, exclude bool) []string {
+	magic := "top,glob"
+	if exclude {
+		magic = "top,glob,exclude"
+	}
+
+	var specs []string
+	for _, pattern := range patterns {
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		// Like gitignore: a leading or inner slash anchors the pattern to the root,
+		// otherwise it matches at any depth
+		anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+		pattern = strings.Trim(pattern, "/")
+		if !anchored {
+			pattern = "**/" + pattern
+		}
+		specs = append(specs,
+			fmt.Sprintf(":(%s)%s", magic, pattern),
+			fmt.Sprintf(":(%s)%s/**", magic, pattern),
+		)
+	}
+	return specs
+}
+
+// diffPathspecArgs returns the pathspec arguments that drop excluded files from a diff
+func (o *DiffOptions) diffPathspecArgs() []string {
+	if o == nil || len(o.Exclude) == 0 {
+		return nil
+	}
+	return append([]string{"--", ":/"}, pathspecs(o.Exclude, true)...)
+}
+
+func (o *DiffOptions) maxFileKB() int {
+	if o == nil || o.MaxFileKB == 0 {
+		return DefaultMaxFileKB
+	}
+	return o.MaxFileKB
+}
+
+// excludedStub stands in for the diff of an excluded file
+func excludedStub(file string, summary string) string {
+	return fileStub(file, "(excluded from prompt: "+summary+")")
+}
+
+// numstatStubs turns `git diff --numstat` output into stubs for excluded files
+func numstatStubs(numstat string) []string {
+	var stubs []string
+	for _, line := range strings.Split(strings.TrimSpace(numstat), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		summary := "binary file changed"
+		var added, deleted int
+		if _, err := fmt.Sscan(fields[0], &added); err == nil {
+			fmt.Sscan(fields[1], &deleted)
+			summary = fmt.Sprintf("%d lines changed", added+deleted)
+		}
+		stubs = append(stubs, excludedStub(fields[2], summary))
+	}
+	return stubs
+}
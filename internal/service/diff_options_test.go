@@ -0,0 +1,73 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoadDiffExcludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, gmcignoreFile, "# lockfiles\npackage-lock.json\n\n*.snap\n")
+
+	got := LoadDiffExcludes(dir, []string{"vendor/"})
+	want := []string{"vendor/", "package-lock.json", "*.snap"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("LoadDiffExcludes() = %v, want %v", got, want)
+	}
+}
+
+func TestPathspecs(t *testing.T) {
+	got := pathspecs([]string{"*.snap", "/build/", "api/gen", "!keep.snap"}, true)
+	want := []string{
+		":(top,glob,exclude)**/*.snap",
+		":(top,glob,exclude)**/*.snap/**",
+		":(top,glob,exclude)build",
+		":(top,glob,exclude)build/**",
+		":(top,glob,exclude)api/gen",
+		":(top,glob,exclude)api/gen/**",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("pathspecs() = %v, want %v", got, want)
+	}
+}
+
+func TestNumstatStubs(t *testing.T) {
+	got := numstatStubs("10\t2\tpackage-lock.json\n-\t-\tlogo.png\n")
+	want := []string{
+		"diff --git a/package-lock.json b/package-lock.json\n(excluded from prompt: 12 lines changed)",
+		"diff --git a/logo.png b/logo.png\n(excluded from prompt: binary file changed)",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("numstatStubs() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffOptionsGitArgs(t *testing.T) {
+	var none *DiffOptions
+	if got := none.gitArgs(); !slices.Equal(got, []string{"--diff-algorithm=minimal"}) {
+		t.Fatalf("nil gitArgs() = %v", got)
+	}
+
+	opts := &DiffOptions{Algorithm: "histogram", ContextLines: 5, FunctionContext: true, IgnoreWhitespace: true, Renames: DiffCopies}
+	want := []string{"--diff-algorithm=histogram", "-U5", "--function-context", "--ignore-all-space", "--find-copies"}
+	if got := opts.gitArgs(); !slices.Equal(got, want) {
+		t.Fatalf("gitArgs() = %v, want %v", got, want)
+	}
+
+	opts = &DiffOptions{ContextLines: DefaultDiffContextLines, Renames: DiffNoRenames}
+	want = []string{"--diff-algorithm=minimal", "-U3", "--no-renames"}
+	if got := opts.gitArgs(); !slices.Equal(got, want) {
+		t.Fatalf("gitArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffOptionsValidate(t *testing.T) {
+	if err := (&DiffOptions{Algorithm: "patience", Renames: DiffRenames}).Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	for _, opts := range []DiffOptions{{Algorithm: "fast"}, {Renames: "yes"}, {ContextLines: -1}} {
+		if err := opts.Validate(); err == nil {
+			t.Fatalf("Validate(%+v) = nil, want error", opts)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import "strings"
+
+// fileDiff is the part of a unified diff that belongs to a single file
+type fileDiff struct {
+	Path string
+	Text string
+}
+
+// splitDiff cuts a multi-file diff at each "diff --git" header
+func splitDiff(diff string) []fileDiff {
+	var files []fileDiff
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			text := strings.TrimRight(strings.Join(current, "\n"), "\n")
+			files = append(files, fileDiff{Path: diffPath(current), Text: text})
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return files
+}
+
+// diffPath returns the post-change path of a file diff, or the old path for deletions
+func diffPath(lines []string) string {
+	var oldPath string
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++ b/"):
+			return strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/"):
+			oldPath = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "rename to "):
+			return strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "@@"):
+			return oldPath
+		}
+	}
+	if oldPath != "" {
+		return oldPath
+	}
+	// Header only (binary, mode change): "diff --git a/x b/x"
+	if _, after, ok := strings.Cut(lines[0], " b/"); ok {
+		return after
+	}
+	return ""
+}
+
+// joinFileDiffs reassembles file diffs into a single diff
+func joinFileDiffs(files []fileDiff) string {
+	parts := make([]string, 0, len(files))
+	for _, f := range files {
+		parts = append(parts, f.Text)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// fileStub stands in for the full diff of file with a one-line summary
+func fileStub(file string, summary string) string {
+	return "diff --git a/" + file + " b/" + file + "\n" + summary
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Size limits for context attached with --context-file and --context-cmd
+const (
+	MaxContextFileBytes = 32 * 1024
+	MaxContextCmdBytes  = 16 * 1024
+)
+
+// ContextFile reads a file attached as extra context, keeping its beginning
+// when it is over MaxContextFileBytes
+func ContextFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read context file: %v", err)
+	}
+	if isBinary(content) {
+		return "", fmt.Errorf("context file %s is binary", path)
+	}
+
+	text := strings.TrimSpace(string(content))
+	if len(text) > MaxContextFileBytes {
+		text = text[:MaxContextFileBytes] + "\n[... truncated]"
+	}
+	return fmt.Sprintf("Contents of %s:\n%s", path, text), nil
+}
+
+// ContextCommand runs command through the shell and returns its combined
+// output, keeping the end when it is over MaxContextCmdBytes since that's
+// where test failures and summaries are. A non-zero exit is not an error:
+// the output of failing tests is exactly what the context is for.
+func ContextCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	output, err := cmd.CombinedOutput()
+	status := "exit status 0"
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		status = exitErr.String()
+	} else if err != nil {
+		return "", fmt.Errorf("failed to run context command %q: %v", command, err)
+	}
+
+	text := strings.TrimSpace(string(output))
+	if len(text) > MaxContextCmdBytes {
+		text = "[truncated ...]\n" + text[len(text)-MaxContextCmdBytes:]
+	}
+	return fmt.Sprintf("Output of `%s` (%s):\n%s", command, status, text), nil
+}
+
+// CombineContext joins the typed context with the attached files and command output
+func CombineContext(userContext string, attachments []string) string {
+	parts := make([]string, 0, len(attachments)+1)
+	if userContext = strings.TrimSpace(userContext); userContext != "" {
+		parts = append(parts, userContext)
+	}
+	parts = append(parts, attachments...)
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestContextFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "design.md", "# Retry\n\nBack off exponentially.\n")
+
+	got, err := ContextFile(dir + "/design.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Contents of " + dir + "/design.md:\n# Retry\n\nBack off exponentially."; got != want {
+		t.Fatalf("ContextFile() = %q, want %q", got, want)
+	}
+
+	writeFile(t, dir, "big.md", strings.Repeat("x", MaxContextFileBytes+10))
+	got, err = ContextFile(dir + "/big.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "x\n[... truncated]") {
+		t.Fatalf("ContextFile() did not truncate: %q", got[len(got)-40:])
+	}
+
+	if _, err := ContextFile(dir + "/missing.md"); err == nil {
+		t.Fatal("ContextFile() on a missing file returned no error")
+	}
+}
+
+func TestContextCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	got, err := ContextCommand("echo FAIL; exit 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Output of `echo FAIL; exit 1` (exit status 1):\nFAIL"; got != want {
+		t.Fatalf("ContextCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestCombineContext(t *testing.T) {
+	if got := CombineContext(" fixes retries ", []string{"a", "b"}); got != "fixes retries\n\na\n\nb" {
+		t.Fatalf("CombineContext() = %q", got)
+	}
+	if got := CombineContext("", []string{"a"}); got != "a" {
+		t.Fatalf("CombineContext() = %q", got)
+	}
+}
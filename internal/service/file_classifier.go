@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultMaxFileKB is the size above which new files are summarised instead of diffed
+const DefaultMaxFileKB = 256
+
+// sniffLen is how much of a file is inspected, the same amount git uses for binary detection
+const sniffLen = 8000
+
+var (
+	generatedHeader = regexp.MustCompile(`(?m)^(// Code generated .* DO NOT EDIT\.|.*@generated\b)`)
+	// generatedDiffHeader finds the marker on an added or context line of a diff
+	generatedDiffHeader = regexp.MustCompile(`(?m)^[+ ]// Code generated .* DO NOT EDIT\.$`)
+
+	binaryKinds = map[string]string{
+		".png": "image", ".jpg": "image", ".jpeg": "image", ".gif": "image",
+		".webp": "image", ".ico": "image", ".bmp": "image", ".avif": "image",
+		".woff": "font", ".woff2": "font", ".ttf": "font", ".otf": "font", ".eot": "font",
+		".zip": "archive", ".gz": "archive", ".tgz": "archive", ".tar": "archive",
+		".jar": "archive", ".7z": "archive", ".pdf": "document",
+		".mp3": "audio", ".wav": "audio", ".ogg": "audio",
+		".mp4": "video", ".webm": "video", ".mov": "video",
+	}
+)
+
+// summarizeNewFile returns a one-line description for new files that would only
+// waste tokens in the prompt: binaries, generated code, minified assets and files
+// over maxKB. It returns "" when the file should be diffed normally.
+func summarizeNewFile(file string, content []byte, maxKB int) string {
+	size := formatSize(len(content))
+	head := content[:min(len(content), sniffLen)]
+
+	if isBinary(content) {
+		kind := "file"
+		if k, ok := binaryKinds[strings.ToLower(filepath.Ext(file))]; ok {
+			kind = k
+		}
+		return fmt.Sprintf("added binary %s %s (%s)", kind, file, size)
+	}
+
+	lines := bytes.Count(content, []byte("\n")) + 1
+	if generatedHeader.Match(head) {
+		return fmt.Sprintf("added generated file %s (%d lines, %s)", file, lines, size)
+	}
+	if isMinified(file, content, lines) {
+		return fmt.Sprintf("added minified asset %s (%s)", file, size)
+	}
+	if maxKB > 0 && len(content) > maxKB*1024 {
+		return fmt.Sprintf("added large file %s (%d lines, %s)", file, lines, size)
+	}
+
+	return ""
+}
+
+// summarizeGeneratedDiffs replaces the diff of generated files with a stub
+func summarizeGeneratedDiffs(diff string) string {
+	if !generatedDiffHeader.MatchString(diff) {
+		return diff
+	}
+
+	files := splitDiff(diff)
+	for i, f := range files {
+		if !generatedDiffHeader.MatchString(f.Text) {
+			continue
+		}
+		changed := 0
+		for _, line := range strings.Split(f.Text, "\n") {
+			if (strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++")) ||
+				(strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---")) {
+				changed++
+			}
+		}
+		files[i].Text = fileStub(f.Path, fmt.Sprintf("updated generated file %s (%d lines changed)", f.Path, changed))
+	}
+
+	return joinFileDiffs(files)
+}
+
+// isBinary reports whether content looks like a binary file, the way git decides
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), sniffLen)], 0) >= 0
+}
+
+// isMinified spots bundled assets by name or by their very long lines
+func isMinified(file string, content []byte, lines int) bool {
+	name := strings.ToLower(file)
+	if strings.HasSuffix(name, ".min.js") || strings.HasSuffix(name, ".min.css") {
+		return true
+	}
+	switch filepath.Ext(name) {
+	case ".js", ".mjs", ".cjs", ".css", ".json", ".svg", ".map":
+		return len(content) > 1024 && len(content)/lines > 500
+	}
+	return false
+}
+
+func formatSize(n int) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%d KB", (n+512)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeNewFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		maxKB   int
+		want    string
+	}{
+		{"source", "main.go", "package main\n", 256, ""},
+		{"binary image", "logo.png", "\x89PNG\r\n\x1a\n\x00\x00" + strings.Repeat("x", 24*1024), 256, "added binary image logo.png (24 KB)"},
+		{"binary unknown", "blob.dat", "a\x00b", 256, "added binary file blob.dat (3 B)"},
+		{"generated go", "api.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n", 256, "added generated file api.pb.go (4 lines, 62 B)"},
+		{"minified by name", "app.min.js", "var a=1;", 256, "added minified asset app.min.js (8 B)"},
+		{"minified bundle", "dist/app.js", strings.Repeat("a", 2048), 256, "added minified asset dist/app.js (2 KB)"},
+		{"large", "data.csv", strings.Repeat("a,b\n", 1024), 2, "added large file data.csv (1025 lines, 4 KB)"},
+		{"limit disabled", "data.csv", strings.Repeat("a,b\n", 1024), -1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeNewFile(tt.file, []byte(tt.content), tt.maxKB); got != tt.want {
+				t.Fatalf("summarizeNewFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeGeneratedDiffs(t *testing.T) {
+	diff := `diff --git a/api.pb.go b/api.pb.go
+new file mode 100644
+--- /dev/null
++++ b/api.pb.go
+@@ -0,0 +1,3 @@
++// Code generated by protoc-gen-go. DO NOT EDIT.
++
++package api
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package old
++package main`
+
+	want := `diff --git a/api.pb.go b/api.pb.go
+updated generated file api.pb.go (3 lines changed)
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package old
++package main`
+
+	if got := summarizeGeneratedDiffs(diff); got != want {
+		t.Fatalf("summarizeGeneratedDiffs() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitDiff_paths(t *testing.T) {
+	diff := `diff --git a/old.go b/new.go
+similarity index 100%
+rename from old.go
+rename to new.go
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ`
+
+	var paths []string
+	for _, f := range splitDiff(diff) {
+		paths = append(paths, f.Path)
+	}
+	if got := strings.Join(paths, ","); got != "new.go,gone.go,logo.png" {
+		t.Fatalf("splitDiff() paths = %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sort"
+	"strings"
+)
+
+// FileContent is the post-change content of a modified file sent alongside its diff
+type FileContent struct {
+	Path    string
+	Content string
+}
+
+// SmallFileContents returns the post-change contents of the modified files of
+// set that show up in diff, smallest first, for as long as they fit in budgetKB together. Files
+// whose diff was replaced by a stub (excluded, generated, lockfiles) and binary
+// or minified files are left out.
+func (g *GitService) SmallFileContents(set *ChangeSet, diff string, budgetKB int) []FileContent {
+	if budgetKB <= 0 {
+		return nil
+	}
+
+	diffed := map[string]bool{}
+	for _, f := range splitDiff(diff) {
+		if strings.Contains(f.Text, "\n@@ ") {
+			diffed[f.Path] = true
+		}
+	}
+
+	var candidates []FileContent
+	for _, c := range set.Changes {
+		// New files are already shown in full by their diff
+		if c.Status == "A" || c.Status == "D" || c.Binary || !diffed[c.Path] {
+			continue
+		}
+		_, content := set.versions(c)
+		if !smallFileCandidate(c.Path, content, budgetKB) {
+			continue
+		}
+		candidates = append(candidates, FileContent{Path: c.Path, Content: string(content)})
+	}
+
+	return fitFileContents(candidates, budgetKB)
+}
+
+// smallFileCandidate reports whether content is readable source small enough for the budget
+func smallFileCandidate(file string, content []byte, budgetKB int) bool {
+	if len(content) == 0 || len(content) > budgetKB*1024 || isBinary(content) {
+		return false
+	}
+	if generatedHeader.Match(content[:min(len(content), sniffLen)]) {
+		return false
+	}
+	return !isMinified(file, content, strings.Count(string(content), "\n")+1)
+}
+
+// fitFileContents keeps the smallest files whose combined size stays within budgetKB
+func fitFileContents(files []FileContent, budgetKB int) []FileContent {
+	sort.SliceStable(files, func(i, j int) bool { return len(files[i].Content) < len(files[j].Content) })
+
+	remaining := budgetKB * 1024
+	var fitted []FileContent
+	for _, f := range files {
+		if len(f.Content) > remaining {
+			break
+		}
+		remaining -= len(f.Content)
+		fitted = append(fitted, f)
+	}
+	sort.Slice(fitted, func(i, j int) bool { return fitted[i].Path < fitted[j].Path })
+	return fitted
+}
+
+// FormatFileContents renders the file contents for the prompt
+func FormatFileContents(files []FileContent) string {
+	parts := make([]string, 0, len(files))
+	for _, f := range files {
+		parts = append(parts, "=== "+f.Path+" ===\n"+strings.TrimRight(f.Content, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -40,18 +40,63 @@ type CommitOptions struct {
 	Push        *bool
 	DryRun      *bool
 	ShowDiff    *bool
-	MaxLength   *int
+	MaxLength   *int // maximum subject line length
 	Language    *string
-	Issue       *string
+	Issues      *[]string // --issue values, e.g. 12 or closes:34
 	NoVerify    *bool
+	LintRules   *LintRules
+	ScopeMap    *map[string]string
+	Diff        *DiffOptions
+	Privacy     *string // full, symbols or stats
+	Neighbors   *string // off, tracked or all
+	IssueConfig *IssueConfig
+	BranchTypes *map[string]string // branch prefix to commit type, on top of DefaultBranchTypes
+	// Finish completes a generated message, e.g. with the fixed header, the issue
+	// references and the trailers, before it is linted
+	Finish func(message string) string
 }
 
 // PreCommitData contains data about the changes to be committed
 type PreCommitData struct {
-	Files        []string
+	Files []string
+	// Diff is the raw diff, or in the stats and symbols privacy modes a
+	// description of the changed files without their code
 	Diff         string
 	RelatedFiles map[string]string
-	Issue        string
+	Issues       []IssueRef
+	BranchType   string // commit type implied by the branch name
+	Scopes       []string
+	Privacy      string
+	// SemanticSummary describes changes to the exported Go API
+	SemanticSummary string
+	Dependencies    []ManifestChanges
+	// OnlyDependencies is set when nothing but manifests and lockfiles changed
+	OnlyDependencies bool
+	// FileContents holds the whole post-change content of small modified files
+	FileContents []FileContent
+
+	// changes and fullFilesKB are kept to narrow the summaries down to the
+	// files picked in auto mode
+	changes     *ChangeSet
+	fullFilesKB int
+}
+
+// PromptDiff returns the diff followed by the summaries computed locally from it
+func (d *PreCommitData) PromptDiff() string {
+	diff := d.Diff
+	if d.SemanticSummary != "" {
+		diff += "\n\nSemantic summary of the Go API changes:\n" + d.SemanticSummary
+	}
+	// Package names are withheld along with the code in the stricter privacy modes
+	if d.Privacy == PrivacyFull || d.Privacy == "" {
+		if deps := FormatDependencyChanges(d.Dependencies); deps != "" {
+			diff += "\n\nDependency changes:\n" + deps
+		}
+	}
+	if len(d.FileContents) > 0 {
+		diff += "\n\nFull contents of small changed files after the change:\n" + FormatFileContents(d.FileContents)
+	}
+	return diff
 }
 
 // SelectFilesAndGenerateCommitOptions contains optional parameters for SelectFilesAndGenerateCommit
@@ -61,7 +106,8 @@ type SelectFilesAndGenerateCommitOptions struct {
 	ModelName    *string
 	MaxLength    *int
 	Language     *string
-	Issue        *string
+	Issues       []IssueRef
+	LintRules    *LintRules
 }
 
 var defaultSafetySettings = []*genai.SafetySetting{
@@ -147,18 +193,19 @@ func (g *GeminiService) analyzeToChannel(
 	message, err := g.AnalyzeChanges(
 		client,
 		ctx,
-		data.Diff,
+		data.PromptDiff(),
 		opts.UserContext,
 		&data.RelatedFiles,
 		opts.Model,
 		opts.MaxLength,
 		opts.Language,
-		&data.Issue,
+		data.Issues,
+		opts.LintRules,
 	)
 	if err != nil {
 		messageChan <- ""
 	} else {
-		messageChan <- message
+		messageChan <- g.EnforceLintRules(client, ctx, message, opts.Model, opts.Language, opts.LintRules, opts.Finish)
 	}
 }
 
@@ -168,7 +215,7 @@ func (g *GeminiService) GetUserPrompt(
 	files []string,
 	maxLength *int,
 	language *string,
-	issue *string,
+	issues []IssueRef,
 	// lastCommits []string,
 ) (string, error) {
 	if *context != "" {
@@ -188,7 +235,7 @@ Neighboring files:
 %s
 
 Requirements:
-- Maximum commit message length: %d characters
+- Maximum subject line length: %d characters
 - Language: %s`,
 		*context,
 		diff,
@@ -219,13 +266,14 @@ func (g *GeminiService) AnalyzeChanges(
 	modelName *string,
 	maxLength *int,
 	language *string,
-	issue *string,
+	issues []IssueRef,
+	lintRules *LintRules,
 	// lastCommits []string,
 ) (string, error) {
 	// format relatedFiles to be dir : files
 	relatedFilesArray := formatRelatedFiles(*relatedFiles)
 
-	userPrompt, err := g.GetUserPrompt(userContext, diff, relatedFilesArray, maxLength, language, issue)
+	userPrompt, err := g.GetUserPrompt(userContext, diff, relatedFilesArray, maxLength, language, issues)
 	if err != nil {
 		return "", err
 	}
@@ -235,13 +283,16 @@ func (g *GeminiService) AnalyzeChanges(
 	if *language != "english" {
 		enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Generate the commit message in %s language.", *language)
 	}
-	enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Keep the commit message under %d characters.", *maxLength)
+	enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Keep the subject line under %d characters.", *maxLength)
+	if section := lintRules.PromptSection(); section != "" {
+		enhancedSystemPrompt += "\n\n" + section
+	}
 
 	temp := g.getModelTemperature(*modelName)
 	var result string
 	for attempt := range 2 {
 		resp, err := geminiClient.Models.GenerateContent(ctx, *modelName, genai.Text(userPrompt), &genai.GenerateContentConfig{
-			Temperature: &temp,
+			Temperature:    &temp,
 			SafetySettings: defaultSafetySettings,
 			SystemInstruction: &genai.Content{
 				Role:  genai.RoleUser,
@@ -300,6 +351,123 @@ func (g *GeminiService) AnalyzeChanges(
 	return result, nil
 }
 
+// maxRepairAttempts bounds how often the model is asked to fix lint violations
+const maxRepairAttempts = 2
+
+// EnforceLintRules finishes message, wraps its body and re-prompts the model with
+// its violations until it passes lintRules or the attempts run out. finish, if
+// set, is applied to every candidate, so what is linted is the message that gets
+// committed. The best message so far is returned; callers are expected to show
+// any remaining violations to the user.
+func (g *GeminiService) EnforceLintRules(
+	geminiClient *genai.Client,
+	ctx context.Context,
+	message string,
+	modelName *string,
+	language *string,
+	lintRules *LintRules,
+	finish func(message string) string,
+) string {
+	prepare := func(message string) string {
+		if finish != nil {
+			message = finish(message)
+		}
+		if lintRules != nil {
+			message = WrapCommitBody(message, lintRules.BodyMaxLineLength)
+		}
+		return message
+	}
+
+	message = prepare(message)
+	violations := LintErrors(LintCommitMessage(message, lintRules))
+	for attempt := 0; attempt < maxRepairAttempts && len(violations) > 0; attempt++ {
+		repaired, err := g.RepairCommitMessage(geminiClient, ctx, message, violations, modelName, language, lintRules)
+		if err != nil {
+			break
+		}
+		repaired = prepare(repaired)
+		repairedViolations := LintErrors(LintCommitMessage(repaired, lintRules))
+		if len(repairedViolations) > len(violations) {
+			continue
+		}
+		message, violations = repaired, repairedViolations
+	}
+	return message
+}
+
+// RepairCommitMessage asks the model to rewrite message so it no longer breaks violations
+func (g *GeminiService) RepairCommitMessage(
+	geminiClient *genai.Client,
+	ctx context.Context,
+	message string,
+	violations []LintViolation,
+	modelName *string,
+	language *string,
+	lintRules *LintRules,
+) (string, error) {
+	prompt := fmt.Sprintf(
+		`The following commit message breaks these rules:
+%s
+
+Rewrite it so it follows every rule while keeping its meaning.
+
+Commit message:
+%s`,
+		FormatLintViolations(violations),
+		message,
+	)
+
+	enhancedSystemPrompt := g.systemPrompt
+	if *language != "english" {
+		enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Generate the commit message in %s language.", *language)
+	}
+	if section := lintRules.PromptSection(); section != "" {
+		enhancedSystemPrompt += "\n\n" + section
+	}
+
+	result, err := g.generateText(geminiClient, ctx, *modelName, enhancedSystemPrompt, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	result = strings.ReplaceAll(result, "```", "")
+	return strings.TrimSpace(result), nil
+}
+
+// generateText sends a single prompt and returns the text of the first candidate
+func (g *GeminiService) generateText(
+	geminiClient *genai.Client,
+	ctx context.Context,
+	modelName string,
+	systemPrompt string,
+	prompt string,
+) (string, error) {
+	temp := g.getModelTemperature(modelName)
+	resp, err := geminiClient.Models.GenerateContent(ctx, modelName, genai.Text(prompt), &genai.GenerateContentConfig{
+		Temperature:    &temp,
+		SafetySettings: defaultSafetySettings,
+		SystemInstruction: &genai.Content{
+			Role:  genai.RoleUser,
+			Parts: []*genai.Part{{Text: systemPrompt}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp == nil || len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil ||
+		len(resp.Candidates[0].Content.Parts) == 0 || resp.Candidates[0].Content.Parts[0] == nil {
+		return "", fmt.Errorf("empty response from model")
+	}
+
+	result := strings.TrimSpace(resp.Candidates[0].Content.Parts[0].Text)
+	if result == "" {
+		return "", fmt.Errorf("empty response text from model")
+	}
+
+	return result, nil
+}
+
 // SelectFilesUsingAI lets the AI determine which files to stage based on the diff and context
 func (g *GeminiService) SelectFilesUsingAI(
 	geminiClient *genai.Client,
@@ -320,7 +488,7 @@ Here's the code diff:
 
 	temp := g.getModelTemperature(*modelName)
 	resp, err := geminiClient.Models.GenerateContent(ctx, *modelName, genai.Text(prompt), &genai.GenerateContentConfig{
-		Temperature: &temp,
+		Temperature:    &temp,
 		SafetySettings: defaultSafetySettings,
 		SystemInstruction: &genai.Content{
 			Role:  genai.RoleModel,
@@ -427,7 +595,7 @@ Neighboring files:
 %s
 
 Requirements:
-- Maximum commit message length: %d characters
+- Maximum subject line length: %d characters
 - Language: %s`,
 		contextStr,
 		diff,
@@ -441,11 +609,14 @@ Requirements:
 	if *opts.Language != "english" {
 		enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Generate the commit message in %s language.", *opts.Language)
 	}
-	enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Keep the commit message under %d characters.", *opts.MaxLength)
+	enhancedSystemPrompt += fmt.Sprintf("\n\nIMPORTANT: Keep the subject line under %d characters.", *opts.MaxLength)
+	if section := opts.LintRules.PromptSection(); section != "" {
+		enhancedSystemPrompt += "\n\n" + section
+	}
 
 	temp := g.getModelTemperature(*opts.ModelName)
 	resp, err := geminiClient.Models.GenerateContent(ctx, *opts.ModelName, genai.Text(prompt), &genai.GenerateContentConfig{
-		Temperature: &temp,
+		Temperature:    &temp,
 		SafetySettings: defaultSafetySettings,
 		SystemInstruction: &genai.Content{
 			Role:  genai.RoleModel,
@@ -1,23 +1,44 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/fatih/color"
 )
 
-type GitService struct{}
+type GitService struct {
+	// base is the commit staged changes are compared with, HEAD when empty
+	base string
+	// root caches GetRepoRoot, which every summary of the changes needs
+	root string
+}
 
 func NewGitService() *GitService {
 	return &GitService{}
 }
 
+// CompareStagedWith makes the staged changes compare with rev instead of HEAD.
+// gmc amend uses HEAD's parent, so the amended commit is described as a whole.
+func (g *GitService) CompareStagedWith(rev string) {
+	g.base = rev
+}
+
+// stagedArgs returns the git diff arguments selecting the staged changes
+func (g *GitService) stagedArgs() []string {
+	if g.base == "" {
+		return []string{"--cached"}
+	}
+	return []string{"--cached", g.base}
+}
+
 func (g *GitService) VerifyGitInstallation() error {
 	if err := exec.Command("git", "--version").Run(); err != nil {
 		return fmt.Errorf("git is not installed. %v", err)
@@ -37,6 +58,35 @@ func (g *GitService) VerifyGitRepository() error {
 	return nil
 }
 
+// GetRepoRoot returns the absolute path of the repository's top-level directory
+func (g *GitService) GetRepoRoot() (string, error) {
+	if g.root != "" {
+		return g.root, nil
+	}
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %v", err)
+	}
+
+	g.root = strings.TrimSpace(string(output))
+	return g.root, nil
+}
+
+// GetPackageRoots returns the directories of tracked go.mod, package.json and
+// Cargo.toml files, relative to the repository root
+func (g *GitService) GetPackageRoots() ([]string, error) {
+	args := []string{"ls-files", "--full-name", "--"}
+	for _, manifest := range packageManifests {
+		args = append(args, ":(top,glob)**/"+manifest)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list package manifests: %v", err)
+	}
+
+	return PackageRoots(strings.Split(strings.TrimSpace(string(output)), "\n")), nil
+}
+
 func (g *GitService) StageAll() error {
 	if err := exec.Command("git", "add", "--all").Run(); err != nil {
 		return fmt.Errorf("failed to update tracked files. %v", err)
@@ -45,9 +95,9 @@ func (g *GitService) StageAll() error {
 	return nil
 }
 
-func (g *GitService) DetectDiffChanges() ([]string, string, error) {
-	files, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal", "--name-only").
-		Output()
+func (g *GitService) DetectDiffChanges(diffOpts *DiffOptions) ([]string, string, error) {
+	nameArgs := append(append([]string{"diff", "--name-only"}, g.stagedArgs()...), diffOpts.gitArgs()...)
+	files, err := exec.Command("git", nameArgs...).Output()
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, "", err
@@ -58,14 +108,68 @@ func (g *GitService) DetectDiffChanges() ([]string, string, error) {
 		return nil, "", fmt.Errorf("nothing to be analyze")
 	}
 
-	diff, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal").
-		Output()
+	args := append(append([]string{"diff"}, g.stagedArgs()...), diffOpts.gitArgs()...)
+	args = append(args, diffOpts.diffPathspecArgs()...)
+	diff, err := exec.Command("git", args...).Output()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return nil, "", err
+	}
+
+	stubs, err := g.excludedDiffStubs(diffOpts, g.stagedArgs())
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil, "", err
 	}
 
-	return strings.Split(filesStr, "\n"), string(diff), nil
+	return strings.Split(filesStr, "\n"), joinDiff(summarizeGeneratedDiffs(string(diff)), stubs), nil
+}
+
+// excludedDiffStubs summarises the tracked files DiffOptions.Exclude dropped
+// from the diff selected by revArgs
+func (g *GitService) excludedDiffStubs(diffOpts *DiffOptions, revArgs []string) ([]string, error) {
+	if diffOpts == nil || len(diffOpts.Exclude) == 0 {
+		return nil, nil
+	}
+
+	args := append(append([]string{"diff", "--numstat"}, diffOpts.gitArgs()...), revArgs...)
+	args = append(append(args, "--"), pathspecs(diffOpts.Exclude, false)...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarise excluded files: %v", err)
+	}
+
+	return numstatStubs(string(output)), nil
+}
+
+// excludedUntrackedFiles returns the untracked files matching DiffOptions.Exclude
+func (g *GitService) excludedUntrackedFiles(diffOpts *DiffOptions) (map[string]bool, error) {
+	excluded := map[string]bool{}
+	if diffOpts == nil || len(diffOpts.Exclude) == 0 {
+		return excluded, nil
+	}
+
+	args := append([]string{"ls-files", "--others", "--exclude-standard", "--full-name", "--"}, pathspecs(diffOpts.Exclude, false)...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list excluded untracked files: %v", err)
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file != "" {
+			excluded[file] = true
+		}
+	}
+
+	return excluded, nil
+}
+
+// joinDiff appends stubs to a diff, separated the same way as per-file diffs
+func joinDiff(diff string, stubs []string) string {
+	parts := stubs
+	if diff = strings.TrimSpace(diff); diff != "" {
+		parts = append([]string{diff}, stubs...)
+	}
+	return strings.Join(parts, "\n\n")
 }
 
 func (g *GitService) GetAllChanges() ([]string, error) {
@@ -130,47 +234,66 @@ func (g *GitService) parseGitPorcelain(output []byte) ([]string, map[string]stri
 	return files, fileStatus, nil
 }
 
-// GetDiffWithUntracked generates a diff that includes both tracked and untracked files
-func (g *GitService) GetDiffWithUntracked() (string, error) {
+// GetDiffWithUntracked generates a diff that includes both tracked and untracked
+// files; the untracked ones are taken from set, the unstaged changes
+func (g *GitService) GetDiffWithUntracked(set *ChangeSet, diffOpts *DiffOptions) (string, error) {
 	var diffParts []string
 
 	// Get diff for tracked files
-	diffCmd := exec.Command("git", "diff", "--diff-algorithm=minimal")
+	args := append(append([]string{"diff"}, diffOpts.gitArgs()...), diffOpts.diffPathspecArgs()...)
+	diffCmd := exec.Command("git", args...)
 	diffOutput, err := diffCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get tracked files diff: %v", err)
 	}
-	trackedDiff := strings.TrimSpace(string(diffOutput))
+	trackedDiff := strings.TrimSpace(summarizeGeneratedDiffs(string(diffOutput)))
 	if trackedDiff != "" {
 		diffParts = append(diffParts, trackedDiff)
 	}
 
-	// Get untracked files and generate diff for each
-	files, fileStatus, err := g.GetAllChangesWithStatus()
+	stubs, err := g.excludedDiffStubs(diffOpts, nil)
+	if err != nil {
+		return "", err
+	}
+	diffParts = append(diffParts, stubs...)
+
+	excludedUntracked, err := g.excludedUntrackedFiles(diffOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to get file status: %v", err)
+		return "", err
 	}
 
-	for _, file := range files {
-		status := fileStatus[file]
-		// "??" means untracked file
-		if status == "??" {
-			// Check if file exists and is readable
-			if _, err := os.Stat(file); os.IsNotExist(err) {
+	for _, c := range set.Changes {
+		if c.Untracked {
+			file := c.Path
+			if excludedUntracked[file] {
+				diffParts = append(diffParts, excludedStub(file, fmt.Sprintf("%d lines added", c.Added)))
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(set.Root, file))
+			if err != nil {
+				continue // Skip files we can't read
+			}
+
+			// Binaries, generated code and the like would only flood the prompt
+			if summary := summarizeNewFile(file, content, diffOpts.maxFileKB()); summary != "" {
+				diffParts = append(diffParts, fileStub(file, summary))
 				continue
 			}
 
 			// Generate diff for untracked file using git diff --no-index
 			// This shows the file as a new file (all lines added)
-			diffCmd := exec.Command("git", "diff", "--no-index", "--no-color", os.DevNull, file)
+			args := append([]string{"diff", "--no-index", "--no-color"}, diffOpts.gitArgs()...)
+			diffCmd := exec.Command("git", append(args, os.DevNull, file)...)
+			diffCmd.Dir = set.Root
 			diffOutput, err := diffCmd.Output()
+			// ponytail: --no-index exits with 1 whenever the files differ, which they always do here
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+				err = nil
+			}
 			if err != nil {
-				// If git diff fails (e.g., binary file), try to read and format as new file
-				content, readErr := os.ReadFile(file)
-				if readErr != nil {
-					continue // Skip files we can't read
-				}
-				// Format as a simple diff showing new file
+				// If git diff fails, format the content as a new file ourselves
 				lines := strings.Split(string(content), "\n")
 				var diffLines []string
 				diffLines = append(diffLines, fmt.Sprintf("diff --git a/%s b/%s", os.DevNull, file))
@@ -196,6 +319,243 @@ func (g *GitService) GetDiffWithUntracked() (string, error) {
 	return strings.Join(diffParts, "\n\n"), nil
 }
 
+// ChangeSet is the list of changed files the diff summaries are built from.
+// Listing it spawns git and reads every untracked file, so it is loaded once
+// per invocation and shared.
+type ChangeSet struct {
+	Root    string
+	Changes []FileChange
+	// oldRev and newRev prefix a path for `git show` to read the file before
+	// and after the change; an empty newRev reads the worktree instead
+	oldRev string
+	newRev string
+	// diffArgs select the same changes for `git diff`
+	diffArgs []string
+}
+
+// only returns a copy of s with just the changes to files
+func (s *ChangeSet) only(files []string) *ChangeSet {
+	subset := *s
+	subset.Changes = nil
+	for _, c := range s.Changes {
+		if slices.Contains(files, c.Path) {
+			subset.Changes = append(subset.Changes, c)
+		}
+	}
+	return &subset
+}
+
+// LoadChanges lists the staged changes, or with staged false the unstaged and
+// untracked ones. Staged changes compare the index with HEAD (or the base set
+// with CompareStagedWith), unstaged ones the worktree with the index.
+func (g *GitService) LoadChanges(staged bool) (*ChangeSet, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	set := &ChangeSet{Root: root, oldRev: ":"}
+	if staged {
+		set.oldRev, set.newRev, set.diffArgs = "HEAD:", ":", g.stagedArgs()
+		if g.base != "" {
+			set.oldRev = g.base + ":"
+		}
+	}
+	if err := set.load(!staged); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// changesBetween lists the changes from the commit from to the commit to, or
+// to the worktree when to is empty
+func (g *GitService) changesBetween(from string, to string) (*ChangeSet, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	set := &ChangeSet{Root: root, oldRev: from + ":", diffArgs: []string{from}}
+	if to != "" {
+		set.newRev = to + ":"
+		set.diffArgs = append(set.diffArgs, to)
+	}
+	if err := set.load(false); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// load fills in the changed files and, with untracked, the untracked ones
+func (s *ChangeSet) load(untracked bool) error {
+	args := append([]string{"diff", "-M"}, s.diffArgs...)
+	nameStatus, err := exec.Command("git", append(args, "--name-status", "-z")...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to get changed files: %v", err)
+	}
+	numstat, err := exec.Command("git", append(args, "--numstat", "-z")...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to get line counts: %v", err)
+	}
+	s.Changes = parseNameStatus(string(nameStatus))
+	applyNumstat(s.Changes, string(numstat))
+
+	if !untracked {
+		return nil
+	}
+
+	output, err := exec.Command("git", "ls-files", "--others", "--exclude-standard", "--full-name", "-z", ":/").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list untracked files: %v", err)
+	}
+	for _, file := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		if file == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.Root, file))
+		if err != nil {
+			continue
+		}
+		s.Changes = append(s.Changes, FileChange{
+			Status:    "A",
+			Path:      file,
+			Added:     strings.Count(string(content), "\n"),
+			Binary:    isBinary(content),
+			Untracked: true,
+		})
+	}
+
+	return nil
+}
+
+// versions returns the content of a changed file before and after the change;
+// a side that doesn't exist is nil
+func (s *ChangeSet) versions(c FileChange) ([]byte, []byte) {
+	var oldSrc, newSrc []byte
+	if c.Status != "A" {
+		oldPath := c.Path
+		if c.OldPath != "" {
+			oldPath = c.OldPath
+		}
+		oldSrc, _ = exec.Command("git", "show", s.oldRev+oldPath).Output()
+	}
+	if c.Status != "D" {
+		if s.newRev == "" {
+			newSrc, _ = os.ReadFile(filepath.Join(s.Root, c.Path))
+		} else {
+			newSrc, _ = exec.Command("git", "show", s.newRev+c.Path).Output()
+		}
+	}
+	return oldSrc, newSrc
+}
+
+// DescribeChanges summarises changes without any source code. In symbols mode
+// each file also lists the declarations it adds, removes and changes.
+func (g *GitService) DescribeChanges(set *ChangeSet, privacy string) (string, error) {
+	// The symbols are filled in on a copy, the set is shared
+	changes := append([]FileChange(nil), set.Changes...)
+
+	if privacy == PrivacySymbols {
+		args := append([]string{"diff", "-M", "-U0", "--no-color"}, set.diffArgs...)
+		hunks, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to get changed lines: %v", err)
+		}
+		hunksByPath := map[string]string{}
+		for _, f := range splitDiff(string(hunks)) {
+			hunksByPath[f.Path] = f.Text
+		}
+
+		for i, c := range changes {
+			if c.Binary {
+				continue
+			}
+			oldSrc, newSrc := set.versions(c)
+			symbols := DiffSymbols(c.Path, oldSrc, newSrc, hunksByPath[c.Path])
+			if len(symbols.Added)+len(symbols.Removed)+len(symbols.Changed) > 0 {
+				changes[i].Symbols = &symbols
+			}
+		}
+	}
+
+	return FormatFileChanges(changes, privacy), nil
+}
+
+// GoSemanticSummary describes how the changed Go files alter the exported API.
+// Files that don't parse are skipped; an empty string means nothing to report.
+func (g *GitService) GoSemanticSummary(set *ChangeSet) string {
+	var apiChanges []GoAPIChanges
+	for _, c := range set.Changes {
+		if !strings.HasSuffix(c.Path, ".go") || c.Binary {
+			continue
+		}
+		oldSrc, newSrc := set.versions(c)
+		if c.OldPath != "" && !strings.HasSuffix(c.OldPath, ".go") {
+			oldSrc = nil
+		}
+		api, err := GoSemanticChanges(c.Path, oldSrc, newSrc)
+		if err != nil {
+			continue
+		}
+		apiChanges = append(apiChanges, api)
+	}
+	dropMovedDecls(apiChanges)
+
+	return FormatSemanticSummary(apiChanges)
+}
+
+// DependencyChanges summarises the changed dependency manifests and lockfiles.
+// The bool reports whether they are the only files that changed.
+func (g *GitService) DependencyChanges(set *ChangeSet) ([]ManifestChanges, bool) {
+	var manifests []ManifestChanges
+	onlyDependencies := len(set.Changes) > 0
+	for _, c := range set.Changes {
+		switch {
+		case isLockfile(c.Path):
+			manifests = append(manifests, ManifestChanges{File: c.Path, Lockfile: true})
+		case isManifest(c.Path):
+			oldSrc, newSrc := set.versions(c)
+			m, err := DiffDependencies(c.Path, oldSrc, newSrc)
+			if err != nil {
+				// Leave unparseable manifests to the model
+				onlyDependencies = false
+				continue
+			}
+			manifests = append(manifests, m)
+		default:
+			onlyDependencies = false
+		}
+	}
+
+	return manifests, onlyDependencies
+}
+
+// CommitMessage is the hash and full message of an existing commit
+type CommitMessage struct {
+	Hash    string
+	Message string
+}
+
+// GetCommitMessages returns the messages of every commit in revisionRange,
+// parents before their children even when the commit dates say otherwise
+func (g *GitService) GetCommitMessages(revisionRange string) ([]CommitMessage, error) {
+	output, err := exec.Command(
+		"git", "log", "--reverse", "--topo-order", "--format=%H%x1f%B%x1e", revisionRange, "--",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in '%s': %v", revisionRange, err)
+	}
+
+	var commits []CommitMessage
+	for _, record := range strings.Split(string(output), "\x1e") {
+		hash, message, ok := strings.Cut(strings.TrimSpace(record), "\x1f")
+		if !ok {
+			continue
+		}
+		commits = append(commits, CommitMessage{Hash: hash, Message: strings.TrimSpace(message)})
+	}
+
+	return commits, nil
+}
+
 func (g *GitService) PushChanges(quiet *bool) error {
 	cmd := exec.Command("git", "push")
 	if !*quiet {
@@ -209,39 +569,57 @@ func (g *GitService) PushChanges(quiet *bool) error {
 	return nil
 }
 
-func (g *GitService) DetectIssueFromBranch() (string, error) {
+func (g *GitService) currentBranch() (string, error) {
 	cmd := exec.Command("git", "branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %v", err)
 	}
+	return strings.TrimSpace(string(output)), nil
+}
 
-	branchName := strings.TrimSpace(string(output))
+// RemoteURL returns the URL of the named remote
+func (g *GitService) RemoteURL(name string) (string, error) {
+	output, err := exec.Command("git", "remote", "get-url", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the URL of remote '%s': %v", name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
 
-	// Common patterns for issue detection in branch names
-	patterns := []string{
-		`(?i)([A-Z]+-\d+)`, // GEN-123, ELI-1220 (case-insensitive)
-		`#(\d+)`,           // #123
-		`(\d+)-`,           // 123-feature
-		`-(\d+)-`,          // feature-123-description
-		`issue-(\d+)`,      // issue-123
-		`fix-(\d+)`,        // fix-123
-		`feat-(\d+)`,       // feat-123
-		`bug-(\d+)`,        // bug-123
+func (g *GitService) DetectIssueFromBranch(issueConfig *IssueConfig) ([]string, error) {
+	branch, err := g.currentBranch()
+	if err != nil {
+		return nil, err
 	}
 
-	for i, pattern := range patterns {
-		if matches := regexp.MustCompile(pattern).FindStringSubmatch(branchName); len(matches) > 1 {
-			result := matches[1]
-			// Convert to uppercase for the first pattern (issue identifiers like GEN-123)
-			if i == 0 {
-				result = strings.ToUpper(result)
-			}
-			return result, nil
-		}
+	return issueConfig.DetectIssues(branch), nil
+}
+
+// DetectTypeFromBranch returns the commit type implied by the branch naming
+// scheme, e.g. fix for hotfix/login, or "" when there is none
+func (g *GitService) DetectTypeFromBranch(mapping map[string]string) (string, error) {
+	branch, err := g.currentBranch()
+	if err != nil {
+		return "", err
 	}
 
-	return "", nil
+	return BranchCommitType(branch, mapping), nil
+}
+
+// SignoffTrailer returns the Signed-off-by trailer `git commit -s` would add,
+// using the committer identity
+func (g *GitService) SignoffTrailer() (string, error) {
+	output, err := exec.Command("git", "var", "GIT_COMMITTER_IDENT").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get committer identity for sign-off: %v", err)
+	}
+	// "Name <email> 1700000000 +0000"
+	ident := strings.TrimSpace(string(output))
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+	return "Signed-off-by: " + ident, nil
 }
 
 func (g *GitService) CommitChangesWithOptions(message string, quiet *bool, noVerify *bool) error {
@@ -251,6 +629,9 @@ func (g *GitService) CommitChangesWithOptions(message string, quiet *bool, noVer
 	}
 
 	cmd := exec.Command("git", args...)
+	// The message was linted before it was confirmed, so gmc's own commit-msg
+	// hook doesn't check it again while the user's other hooks still run
+	cmd.Env = append(os.Environ(), ValidatedMessageEnv+"=1")
 	if !*quiet {
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
@@ -270,6 +651,12 @@ func (g *GitService) DetectAndPrepareChanges(opts *CommitOptions) (*PreCommitDat
 		}
 	}
 
+	// The diff and every summary of it start from the same list of changed files
+	changes, err := g.LoadChanges(!*opts.AutoSelect)
+	if err != nil {
+		return nil, err
+	}
+
 	filesChan := make(chan []string, 1)
 	diffChan := make(chan string, 1)
 
@@ -291,7 +678,7 @@ func (g *GitService) DetectAndPrepareChanges(opts *CommitOptions) (*PreCommitDat
 				}
 
 				// Get full diff of all changes including untracked files
-				diff, err = g.GetDiffWithUntracked()
+				diff, err = g.GetDiffWithUntracked(changes, opts.Diff)
 				if err != nil {
 					filesChan <- []string{}
 					diffChan <- ""
@@ -301,7 +688,7 @@ func (g *GitService) DetectAndPrepareChanges(opts *CommitOptions) (*PreCommitDat
 				files = allChanges
 			} else {
 				// For normal flow, get only staged changes
-				files, diff, err = g.DetectDiffChanges()
+				files, diff, err = g.DetectDiffChanges(opts.Diff)
 				if err != nil {
 					filesChan <- []string{}
 					diffChan <- ""
@@ -330,49 +717,109 @@ func (g *GitService) DetectAndPrepareChanges(opts *CommitOptions) (*PreCommitDat
 		}
 	}
 
-	relatedFiles := g.getRelatedFiles(files)
+	privacy := PrivacyFull
+	if opts.Privacy != nil && *opts.Privacy != "" {
+		privacy = *opts.Privacy
+	}
+	data := &PreCommitData{Files: files, Diff: diff, Privacy: privacy}
+	if err := g.summarizeChanges(data, changes, opts.Diff); err != nil {
+		return nil, err
+	}
+
+	neighbors := NeighborsTracked
+	if opts.Neighbors != nil && *opts.Neighbors != "" {
+		neighbors = *opts.Neighbors
+	}
+	data.RelatedFiles = g.getRelatedFiles(changes.Root, files, neighbors)
 
-	// Auto-detect issue number from branch name if not provided
-	issue := *opts.Issue
-	if issue == "" {
-		detectedIssue, err := g.DetectIssueFromBranch()
-		if err == nil && detectedIssue != "" {
-			issue = detectedIssue
-			if !*opts.Quiet {
-				color.New(color.FgCyan).Printf("Auto-detected issue: %s\n", detectedIssue)
+	// Auto-detect issues from the branch name and the user context if none were given
+	var issues []IssueRef
+	if opts.Issues != nil {
+		issues = ParseIssueRefs(*opts.Issues)
+	}
+	if len(issues) == 0 {
+		detected, _ := g.DetectIssueFromBranch(opts.IssueConfig)
+		for _, id := range detected {
+			issues = append(issues, IssueRef{ID: id})
+		}
+		if opts.UserContext != nil {
+			issues = MergeIssueRefs(issues, opts.IssueConfig.DetectContextIssues(*opts.UserContext))
+		}
+		if len(issues) > 0 && !*opts.Quiet {
+			ids := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				ids = append(ids, issue.ID)
 			}
+			color.New(color.FgCyan).Printf("Auto-detected issues: %s\n", strings.Join(ids, ", "))
 		}
 	}
 
-	return &PreCommitData{
-		Files:        files,
-		Diff:         diff,
-		RelatedFiles: relatedFiles,
-		Issue:        issue,
-	}, nil
-}
+	var branchTypes map[string]string
+	if opts.BranchTypes != nil {
+		branchTypes = *opts.BranchTypes
+	}
+	data.BranchType, _ = g.DetectTypeFromBranch(branchTypes)
+
+	// Derive the scope from the staged paths so a folder always gets the same scope.
+	// In auto mode the AI picks the files later, so there is nothing to derive yet.
+	if !*opts.AutoSelect {
+		var scopeMap map[string]string
+		if opts.ScopeMap != nil {
+			scopeMap = *opts.ScopeMap
+		}
+		roots, _ := g.GetPackageRoots()
+		data.Scopes = ResolveScopes(files, scopeMap, roots)
+	}
 
-// getRelatedFiles discovers related files in the same directories
-func (g *GitService) getRelatedFiles(files []string) map[string]string {
-	relatedFiles := make(map[string]string)
-	visitedDirs := make(map[string]bool)
+	data.Issues = issues
+	return data, nil
+}
 
-	for _, file := range files {
-		dir := filepath.Dir(file)
-		if !visitedDirs[dir] {
-			lsEntry, err := os.ReadDir(dir)
-			if err == nil {
-				var ls []string
-				for _, entry := range lsEntry {
-					ls = append(ls, entry.Name())
-				}
-				relatedFiles[dir] = strings.Join(ls, ", ")
-				visitedDirs[dir] = true
-			}
+// summarizeChanges fills in what data sends instead of, or next to, the raw
+// diff: the privacy summary, the dependency list, the Go API changes and the
+// contents of small files
+func (g *GitService) summarizeChanges(data *PreCommitData, set *ChangeSet, diffOpts *DiffOptions) error {
+	// Privacy modes send a description of the change's structure instead of the code
+	if data.Privacy != PrivacyFull {
+		summary, err := g.DescribeChanges(set, data.Privacy)
+		if err != nil {
+			return err
 		}
+		data.Diff = summary
+	}
+
+	// Lockfile hashes and manifest version bumps are condensed into one list
+	data.Dependencies, data.OnlyDependencies = g.DependencyChanges(set)
+	if data.Privacy != PrivacyFull {
+		return nil
 	}
+	data.Diff = stripDependencyDiffs(data.Diff, data.Dependencies)
 
-	return relatedFiles
+	data.changes = set
+	if diffOpts != nil {
+		data.fullFilesKB = diffOpts.FullFilesKB
+	}
+	g.summarizeFiles(data, set)
+	return nil
+}
+
+// summarizeFiles fills in the Go API changes and the small file contents of
+// set. Both are derived from the code, so only full mode sends them.
+func (g *GitService) summarizeFiles(data *PreCommitData, set *ChangeSet) {
+	data.SemanticSummary = g.GoSemanticSummary(set)
+	// Small files are sent whole so the model sees more than a few lines around each hunk
+	data.FileContents = g.SmallFileContents(set, data.Diff, data.fullFilesKB)
+}
+
+// SelectFiles returns a copy of data for committing only files, as picked in
+// auto mode, with the Go API changes and file contents narrowed down to them
+func (g *GitService) SelectFiles(data *PreCommitData, files []string) *PreCommitData {
+	selected := *data
+	selected.Files = files
+	if data.changes != nil && data.Privacy == PrivacyFull {
+		g.summarizeFiles(&selected, data.changes.only(files))
+	}
+	return &selected
 }
 
 // ResetStaged resets the staged area, unstaging all files
@@ -432,7 +879,74 @@ func (g *GitService) ConfirmAction(message string, quiet *bool, push *bool, dryR
 	return nil
 }
 
-func (g *GitService) GetDiff() (*PreCommitData, error) {
+// emptyTree is the object ID of git's empty tree, the parent of a root commit
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// AmendBase returns what the amended commit is compared with: HEAD's parent,
+// or the empty tree when HEAD is the first commit
+func (g *GitService) AmendBase() (string, error) {
+	if err := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		return "", fmt.Errorf("there is no commit to amend yet")
+	}
+	parent, err := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD^").Output()
+	if err != nil {
+		return emptyTree, nil
+	}
+	return strings.TrimSpace(string(parent)), nil
+}
+
+// HeadMessage returns the full message of HEAD
+func (g *GitService) HeadMessage() (string, error) {
+	output, err := exec.Command("git", "log", "-1", "--format=%B", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the last commit message: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsHeadPushed reports whether HEAD is on any remote-tracking branch, so
+// amending it would rewrite published history
+func (g *GitService) IsHeadPushed() (bool, error) {
+	output, err := exec.Command("git", "branch", "--remotes", "--contains", "HEAD").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether HEAD was pushed: %v", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// AmendCommit replaces HEAD with the staged changes and message, keeping its author
+func (g *GitService) AmendCommit(message string, quiet *bool, dryRun *bool, noVerify *bool) error {
+	if *dryRun {
+		if !*quiet {
+			color.New(color.FgYellow).Println("🔍 DRY RUN - No changes will be made")
+			color.New(color.FgCyan).Printf("Would amend the last commit with message: %s\n", message)
+		}
+		return nil
+	}
+
+	args := []string{"commit", "--amend", "-m", strings.TrimSpace(message)}
+	if *noVerify {
+		args = append(args, "--no-verify")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), ValidatedMessageEnv+"=1")
+	if !*quiet {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to amend the last commit. %v", err)
+	}
+
+	if !*quiet {
+		color.New(color.FgGreen).Println("✔ Successfully amended!")
+	}
+	return nil
+}
+
+// GetDiff returns the changes of the branch compared with the remote's default
+// branch, described without code in the symbols and stats privacy modes
+func (g *GitService) GetDiff(diffOpts *DiffOptions, privacy string) (*PreCommitData, error) {
 	// Get all remotes
 	remotesOutput, err := exec.Command("git", "remote").Output()
 	if err != nil {
@@ -477,21 +991,34 @@ func (g *GitService) GetDiff() (*PreCommitData, error) {
 	headBranchName := headBranchMatch[1]
 
 	// Diff against the remote's HEAD branch
-	diff, err := exec.Command(
-		"git",
-		"diff",
-		fmt.Sprintf("%s/%s", remoteName, headBranchName),
-	).Output()
+	target := fmt.Sprintf("%s/%s", remoteName, headBranchName)
+	data := &PreCommitData{
+		Files:        []string{},
+		RelatedFiles: map[string]string{},
+		Privacy:      privacy,
+	}
+
+	// Privacy modes describe the branch's changes instead of sending the code
+	if privacy != PrivacyFull {
+		set, err := g.changesBetween(target, "")
+		if err != nil {
+			return nil, err
+		}
+		data.Diff, err = g.DescribeChanges(set, privacy)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	args := append([]string{"diff"}, diffOpts.gitArgs()...)
+	diff, err := exec.Command("git", append(args, target)...).Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get diff against '%s/%s': %v", remoteName, headBranchName, err)
+		return nil, fmt.Errorf("failed to get diff against '%s': %v", target, err)
 	}
+	data.Diff = string(diff)
 
-	return &PreCommitData{
-		Diff:         string(diff),
-		Files:        []string{},
-		RelatedFiles: map[string]string{},
-		Issue:        "",
-	}, nil
+	return data, nil
 }
 
 func (g *GitService) CreatePullRequest(
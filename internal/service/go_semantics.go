@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GoAPIChanges is the semantic difference between two versions of a Go file
+type GoAPIChanges struct {
+	File             string
+	Added            []string
+	Removed          []string
+	Renamed          []string // "func Old -> func New"
+	SignatureChanged []string // "func Get(k string) -> func Get(ctx context.Context, k string)"
+	NewTests         []string
+	// Private is set for internal packages and package main, whose exported
+	// identifiers can't be imported from outside the module
+	Private bool
+}
+
+// Breaking reports whether the change removes or alters exported API
+func (c GoAPIChanges) Breaking() bool {
+	if c.Private {
+		return false
+	}
+	return len(c.Removed) > 0 || len(c.Renamed) > 0 || len(c.SignatureChanged) > 0
+}
+
+func (c GoAPIChanges) empty() bool {
+	return len(c.Added)+len(c.Removed)+len(c.Renamed)+len(c.SignatureChanged)+len(c.NewTests) == 0
+}
+
+// goDecl is an exported top-level declaration
+type goDecl struct {
+	kind string // func, type, const or var
+	name string // methods are qualified with their receiver type
+	// signature is the parameter and result list of funcs, the type of consts and vars
+	signature string
+	// body is the type expression of type declarations, used to spot renames
+	body string
+}
+
+func (d goDecl) key() string {
+	return d.kind + " " + d.name
+}
+
+func (d goDecl) String() string {
+	if d.kind == "func" {
+		return "func " + d.name + d.signature
+	}
+	return d.key()
+}
+
+// GoSemanticChanges compares the exported identifiers of two versions of a Go
+// file. oldSrc is nil for new files and newSrc nil for deleted ones. In test
+// files only new Test, Benchmark, Fuzz and Example functions are reported.
+func GoSemanticChanges(path string, oldSrc, newSrc []byte) (GoAPIChanges, error) {
+	changes := GoAPIChanges{File: path}
+
+	oldPkg, oldDecls, oldTests, err := goExportedDecls(oldSrc)
+	if err != nil {
+		return changes, err
+	}
+	newPkg, newDecls, newTests, err := goExportedDecls(newSrc)
+	if err != nil {
+		return changes, err
+	}
+	changes.Private = oldPkg == "main" || newPkg == "main" ||
+		slices.Contains(strings.Split(filepath.ToSlash(filepath.Dir(path)), "/"), "internal")
+
+	if strings.HasSuffix(path, "_test.go") {
+		for name := range newTests {
+			if !oldTests[name] {
+				changes.NewTests = append(changes.NewTests, name)
+			}
+		}
+		sort.Strings(changes.NewTests)
+		return changes, nil
+	}
+
+	var added, removed []goDecl
+	for key, d := range newDecls {
+		old, ok := oldDecls[key]
+		switch {
+		case !ok:
+			added = append(added, d)
+		case d.kind == "func" && old.signature != d.signature:
+			changes.SignatureChanged = append(changes.SignatureChanged, old.String()+" -> "+d.String())
+		}
+	}
+	for key, d := range oldDecls {
+		if _, ok := newDecls[key]; !ok {
+			removed = append(removed, d)
+		}
+	}
+	sortDecls(added)
+	sortDecls(removed)
+
+	// A removed and an added declaration with the same shape is a rename
+	for _, r := range removed {
+		renamed := false
+		for i, a := range added {
+			if a.kind == r.kind && a.signature == r.signature && a.body == r.body && (a.signature != "" || a.body != "") {
+				changes.Renamed = append(changes.Renamed, r.key()+" -> "+a.key())
+				added = append(added[:i], added[i+1:]...)
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			changes.Removed = append(changes.Removed, r.key())
+		}
+	}
+	for _, a := range added {
+		changes.Added = append(changes.Added, a.key())
+	}
+	sort.Strings(changes.SignatureChanged)
+
+	return changes, nil
+}
+
+// goExportedDecls returns the package name, the exported declarations and the
+// test functions of src
+func goExportedDecls(src []byte) (string, map[string]goDecl, map[string]bool, error) {
+	decls := map[string]goDecl{}
+	tests := map[string]bool{}
+	if src == nil {
+		return "", decls, tests, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	render := func(node ast.Node) string {
+		if node == nil {
+			return ""
+		}
+		var buf bytes.Buffer
+		printer.Fprint(&buf, fset, node)
+		return buf.String()
+	}
+	add := func(d goDecl) {
+		decls[d.key()] = d
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && isTestFunc(d.Name.Name) {
+				tests[d.Name.Name] = true
+			}
+			name := goFuncName(d)
+			if !exportedPath(name) {
+				continue
+			}
+			add(goDecl{kind: "func", name: name, signature: strings.TrimPrefix(render(d.Type), "func")})
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						add(goDecl{kind: "type", name: s.Name.Name, body: render(s.Type)})
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.IsExported() {
+							add(goDecl{kind: d.Tok.String(), name: n.Name, signature: render(s.Type)})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return file.Name.Name, decls, tests, nil
+}
+
+// exportedPath reports whether every part of a possibly receiver-qualified name is exported
+func exportedPath(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !token.IsExported(part) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTestFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortDecls(decls []goDecl) {
+	sort.Slice(decls, func(i, j int) bool { return decls[i].key() < decls[j].key() })
+}
+
+// dropMovedDecls removes declarations that were only moved between files of the same package
+func dropMovedDecls(changes []GoAPIChanges) {
+	for i := range changes {
+		for j := range changes {
+			if i == j || filepath.Dir(changes[i].File) != filepath.Dir(changes[j].File) {
+				continue
+			}
+			for _, removed := range slices.Clone(changes[i].Removed) {
+				if k := slices.Index(changes[j].Added, removed); k >= 0 {
+					changes[j].Added = slices.Delete(changes[j].Added, k, k+1)
+					changes[i].Removed = slices.DeleteFunc(changes[i].Removed, func(s string) bool { return s == removed })
+				}
+			}
+		}
+	}
+}
+
+// FormatSemanticSummary renders the Go API changes for the prompt
+func FormatSemanticSummary(changes []GoAPIChanges) string {
+	var b strings.Builder
+	breaking := false
+
+	for _, c := range changes {
+		if c.empty() {
+			continue
+		}
+		b.WriteString(c.File + "\n")
+		for _, group := range []struct {
+			label    string
+			items    []string
+			breaking bool
+		}{
+			{"added", c.Added, false},
+			{"removed", c.Removed, true},
+			{"renamed", c.Renamed, true},
+			{"signature changed", c.SignatureChanged, true},
+			{"new tests", c.NewTests, false},
+		} {
+			if len(group.items) == 0 {
+				continue
+			}
+			suffix := ""
+			if group.breaking && !c.Private {
+				suffix = " [BREAKING]"
+			}
+			fmt.Fprintf(&b, "  %s: %s%s\n", group.label, strings.Join(group.items, "; "), suffix)
+		}
+		breaking = breaking || c.Breaking()
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+	if breaking {
+		b.WriteString("Exported Go API was removed or changed incompatibly: mark the commit as breaking with \"!\" after the type/scope and explain it in a BREAKING CHANGE footer.\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
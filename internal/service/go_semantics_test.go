@@ -0,0 +1,128 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGoSemanticChanges(t *testing.T) {
+	oldSrc := `package svc
+
+type Store struct{ items map[string]string }
+
+func (s *Store) Get(k string) string { return s.items[k] }
+
+func Open(path string) (*Store, error) { return nil, nil }
+
+func Legacy() {}
+
+func helper() {}
+`
+	newSrc := `package svc
+
+type Store struct{ items map[string]string }
+
+func (s *Store) Get(ctx context.Context, k string) string { return s.items[k] }
+
+func Load(path string) (*Store, error) { return nil, nil }
+
+const Version = "1.0"
+
+func helper2() {}
+`
+
+	got, err := GoSemanticChanges("svc/store.go", []byte(oldSrc), []byte(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Added, []string{"const Version"}) {
+		t.Fatalf("Added = %v", got.Added)
+	}
+	if !slices.Equal(got.Removed, []string{"func Legacy"}) {
+		t.Fatalf("Removed = %v", got.Removed)
+	}
+	if !slices.Equal(got.Renamed, []string{"func Open -> func Load"}) {
+		t.Fatalf("Renamed = %v", got.Renamed)
+	}
+	want := "func Store.Get(k string) string -> func Store.Get(ctx context.Context, k string) string"
+	if !slices.Equal(got.SignatureChanged, []string{want}) {
+		t.Fatalf("SignatureChanged = %v", got.SignatureChanged)
+	}
+	if !got.Breaking() {
+		t.Fatal("Breaking() = false, want true")
+	}
+}
+
+func TestGoSemanticChanges_private(t *testing.T) {
+	oldSrc := []byte("package svc\n\nfunc Legacy() {}\n")
+	newSrc := []byte("package svc\n")
+	for _, tc := range []struct {
+		path    string
+		oldSrc  []byte
+		newSrc  []byte
+		private bool
+	}{
+		{"svc/store.go", oldSrc, newSrc, false},
+		{"internal/svc/store.go", oldSrc, newSrc, true},
+		{"pkg/internal/store.go", oldSrc, newSrc, true},
+		{"internal.go", oldSrc, newSrc, false},
+		{"cmd/gmc/main.go", []byte("package main\n\nfunc Run() {}\n"), []byte("package main\n"), true},
+	} {
+		got, err := GoSemanticChanges(tc.path, tc.oldSrc, tc.newSrc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Private != tc.private || got.Breaking() == tc.private {
+			t.Errorf("GoSemanticChanges(%s) Private = %v, Breaking() = %v, want private %v", tc.path, got.Private, got.Breaking(), tc.private)
+		}
+		summary := FormatSemanticSummary([]GoAPIChanges{got})
+		if strings.Contains(summary, "BREAKING") == tc.private {
+			t.Errorf("FormatSemanticSummary(%s) = %q", tc.path, summary)
+		}
+	}
+}
+
+func TestGoSemanticChanges_newTests(t *testing.T) {
+	oldSrc := "package svc\n\nfunc TestGet(t *testing.T) {}\n"
+	newSrc := "package svc\n\nfunc TestGet(t *testing.T) {}\n\nfunc TestLoad(t *testing.T) {}\n\nfunc BenchmarkLoad(b *testing.B) {}\n"
+
+	got, err := GoSemanticChanges("svc/store_test.go", []byte(oldSrc), []byte(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.NewTests, []string{"BenchmarkLoad", "TestLoad"}) {
+		t.Fatalf("NewTests = %v", got.NewTests)
+	}
+	if got.Breaking() || len(got.Added) > 0 {
+		t.Fatalf("GoSemanticChanges() = %+v, want only new tests", got)
+	}
+}
+
+func TestDropMovedDecls(t *testing.T) {
+	changes := []GoAPIChanges{
+		{File: "svc/a.go", Removed: []string{"func Get", "func Gone"}},
+		{File: "svc/b.go", Added: []string{"func Get"}},
+		{File: "other/c.go", Added: []string{"func Gone"}},
+	}
+	dropMovedDecls(changes)
+
+	if !slices.Equal(changes[0].Removed, []string{"func Gone"}) || len(changes[1].Added) != 0 {
+		t.Fatalf("dropMovedDecls() = %+v", changes)
+	}
+}
+
+func TestFormatSemanticSummary(t *testing.T) {
+	summary := FormatSemanticSummary([]GoAPIChanges{
+		{File: "svc/a.go", Added: []string{"func New"}},
+		{File: "svc/b.go"},
+	})
+	if summary != "svc/a.go\n  added: func New" {
+		t.Fatalf("FormatSemanticSummary() = %q", summary)
+	}
+
+	summary = FormatSemanticSummary([]GoAPIChanges{{File: "svc/a.go", Removed: []string{"func Old"}}})
+	if !strings.Contains(summary, "removed: func Old [BREAKING]") || !strings.Contains(summary, "BREAKING CHANGE footer") {
+		t.Fatalf("FormatSemanticSummary() = %q", summary)
+	}
+}
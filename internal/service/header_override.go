@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	overrideTypePattern  = regexp.MustCompile(`^\w+$`)
+	breakingFooterPrefix = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: \S`)
+)
+
+// HeaderOverride is the part of the header fixed with --type, --scope and
+// --breaking. The model only writes the description.
+type HeaderOverride struct {
+	Type     string
+	Scope    string
+	Breaking bool
+}
+
+// IsZero reports whether nothing was fixed on the command line
+func (o HeaderOverride) IsZero() bool {
+	return o.Type == "" && o.Scope == "" && !o.Breaking
+}
+
+// Validate checks that the type and scope can be written into a header
+func (o HeaderOverride) Validate() error {
+	if o.Type != "" && !overrideTypePattern.MatchString(o.Type) {
+		return fmt.Errorf("invalid --type %q, expected a single word like feat or fix", o.Type)
+	}
+	if strings.ContainsAny(o.Scope, "()\r\n") {
+		return fmt.Errorf("invalid --scope %q, it may not contain parentheses or newlines", o.Scope)
+	}
+	return nil
+}
+
+// Apply rewrites the header of message to the fixed type, scope and breaking
+// flag, keeping the model's description. A header that isn't in Conventional
+// Commits form is taken as the description as a whole. A breaking change the
+// model didn't describe gets a BREAKING CHANGE footer repeating the subject,
+// so --breaking holds even when the message isn't reviewed.
+func (o HeaderOverride) Apply(message string) string {
+	if o.IsZero() || strings.TrimSpace(message) == "" {
+		return message
+	}
+
+	header, rest, hasBody := strings.Cut(message, "\n")
+	parsed, ok := ParseCommitHeader(strings.TrimSpace(header))
+	if !ok {
+		parsed = CommitHeader{Subject: strings.TrimSpace(header)}
+	}
+	if o.Type != "" {
+		parsed.Type = o.Type
+	}
+	if parsed.Type == "" {
+		// The model gave no type to keep, so one has to be picked
+		parsed.Type = "chore"
+	}
+	if o.Scope != "" {
+		parsed.Scope = o.Scope
+	}
+	parsed.Breaking = parsed.Breaking || o.Breaking
+
+	header = parsed.Type
+	if parsed.Scope != "" {
+		header += "(" + parsed.Scope + ")"
+	}
+	if parsed.Breaking {
+		header += "!"
+	}
+	header += ": " + parsed.Subject
+
+	message = header
+	if hasBody {
+		message += "\n" + rest
+	}
+	if o.Breaking && !hasBreakingFooter(message) {
+		message = AppendTrailers(message, []string{"BREAKING CHANGE: " + parsed.Subject})
+	}
+	return message
+}
+
+// hasBreakingFooter reports whether message describes its breaking change in a
+// BREAKING CHANGE footer
+func hasBreakingFooter(message string) bool {
+	_, rest, _ := strings.Cut(message, "\n")
+	return breakingFooterPrefix.MatchString(rest)
+}
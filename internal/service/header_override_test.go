@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestHeaderOverride_Apply(t *testing.T) {
+	cases := []struct {
+		override HeaderOverride
+		message  string
+		want     string
+	}{
+		{HeaderOverride{Type: "fix"}, "feat(api): handle empty body", "fix(api): handle empty body"},
+		{HeaderOverride{Scope: "cli"}, "feat(api): add flag", "feat(cli): add flag"},
+		{HeaderOverride{Type: "fix", Scope: "api"}, "handle empty body\n\nBody.", "fix(api): handle empty body\n\nBody."},
+		{HeaderOverride{Breaking: true}, "feat: drop v1", "feat!: drop v1\n\nBREAKING CHANGE: drop v1"},
+		{HeaderOverride{Breaking: true}, "feat: drop v1\n\nThe v1 API is gone.", "feat!: drop v1\n\nThe v1 API is gone.\n\nBREAKING CHANGE: drop v1"},
+		{HeaderOverride{Breaking: true}, "feat!: drop v1\n\nBREAKING CHANGE: use v2", "feat!: drop v1\n\nBREAKING CHANGE: use v2"},
+		{HeaderOverride{Scope: "api"}, "add flag", "chore(api): add flag"},
+		{HeaderOverride{Type: "fix"}, "feat!: drop v1\n\nBREAKING CHANGE: gone", "fix!: drop v1\n\nBREAKING CHANGE: gone"},
+		{HeaderOverride{}, "feat: unchanged", "feat: unchanged"},
+		{HeaderOverride{Type: "fix"}, "", ""},
+	}
+	for _, tc := range cases {
+		if got := tc.override.Apply(tc.message); got != tc.want {
+			t.Errorf("%+v.Apply(%q) = %q, want %q", tc.override, tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestHeaderOverride_Validate(t *testing.T) {
+	if err := (HeaderOverride{Type: "fix", Scope: "api/v2"}).Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	for _, o := range []HeaderOverride{{Type: "fix: x"}, {Type: "a b"}, {Scope: "a)"}} {
+		if err := o.Validate(); err == nil {
+			t.Errorf("%+v.Validate() = nil, want error", o)
+		}
+	}
+}
+
+func TestLintRules_WithOverride(t *testing.T) {
+	rules := (&LintRules{Types: []string{"feat", "fix"}, Scopes: []string{"api"}}).
+		WithOverride(HeaderOverride{Type: "chore", Scope: "cli", Breaking: true})
+
+	got := lintRuleIDs(LintCommitMessage("feat(api): drop v1", rules))
+	want := []string{"type-enum", "scope-enum", "header-breaking", "footer-breaking-change"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("LintCommitMessage() rules = %v, want %v", got, want)
+	}
+	if got := LintCommitMessage("chore(cli)!: drop v1\n\nBREAKING CHANGE: the v1 flags are gone", rules); len(got) != 0 {
+		t.Fatalf("LintCommitMessage() = %v, want no violations", got)
+	}
+}
+
+func TestEnforceLintRules_lintsFinishedMessage(t *testing.T) {
+	override := HeaderOverride{Type: "chore", Scope: "cli", Breaking: true}
+	rules := DefaultLintRules(72, 72).WithOverride(override)
+
+	// The raw output breaks the override's rules, but once the override is
+	// applied nothing is left to repair, so the model is never asked (the nil
+	// client would fail if it were)
+	got := NewGeminiService().EnforceLintRules(nil, context.Background(), "feat(api): drop v1", nil, nil, rules, override.Apply)
+	if want := "chore(cli)!: drop v1\n\nBREAKING CHANGE: drop v1"; got != want {
+		t.Fatalf("EnforceLintRules() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The git hooks geminicommit can install
+const (
+	// PrepareCommitMsgHook fills in the message before the editor opens
+	PrepareCommitMsgHook = "prepare-commit-msg"
+	// CommitMsgHook lints the message once it is written
+	CommitMsgHook = "commit-msg"
+)
+
+// ValidatedMessageEnv is set for the commits gmc makes itself. Their message
+// was linted before it was shown, so the commit-msg hook lets them through.
+const ValidatedMessageEnv = "GMC_MESSAGE_VALIDATED"
+
+// hookMarker identifies hooks written by geminicommit, so they are never
+// mistaken for the user's own
+const hookMarker = "# installed by geminicommit"
+
+// hookBackupSuffix is appended to a hook that was there before geminicommit's.
+// The installed hook runs it first and uninstall puts it back.
+const hookBackupSuffix = ".pre-gmc"
+
+// HooksDir returns the directory git runs hooks from, honouring core.hooksPath
+func (g *GitService) HooksDir() (string, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return "", err
+	}
+	output, err := exec.Command("git", "-C", root, "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the hooks directory: %v", err)
+	}
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	return dir, nil
+}
+
+// HookScript returns the shell script for hook, which chains to the hook it
+// replaced and then hands its arguments to `gmc hook run`, after flags
+func HookScript(hook string, executable string, flags ...string) string {
+	run := append([]string{"hook", "run", "--hook", hook}, flags...)
+	return fmt.Sprintf(`#!/bin/sh
+%s
+previous="$(dirname "$0")/%s%s"
+if [ -x "$previous" ]; then
+	"$previous" "$@" || exit $?
+fi
+exec '%s' %s "$@"
+`, hookMarker, hook, hookBackupSuffix, strings.ReplaceAll(executable, "'", `'\''`), strings.Join(run, " "))
+}
+
+// InstallHook writes script as hook in dir. A hook geminicommit didn't write
+// is kept next to it and run first; its new path is returned.
+func InstallHook(dir string, hook string, script string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %v", err)
+	}
+
+	path := filepath.Join(dir, hook)
+	existing, err := os.ReadFile(path)
+	backup := ""
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", fmt.Errorf("failed to read existing %s hook: %v", hook, err)
+	case !strings.Contains(string(existing), hookMarker):
+		backup = path + hookBackupSuffix
+		if _, err := os.Stat(backup); err == nil {
+			return "", fmt.Errorf("both %s and %s exist, move one of them away first", path, backup)
+		}
+		if err := os.Rename(path, backup); err != nil {
+			return "", fmt.Errorf("failed to keep existing %s hook: %v", hook, err)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return backup, fmt.Errorf("failed to write %s hook: %v", hook, err)
+	}
+	return backup, nil
+}
+
+// UninstallHook removes geminicommit's hook from dir and puts back the hook it
+// replaced, if any; the result reports whether that happened
+func UninstallHook(dir string, hook string) (bool, error) {
+	path := filepath.Join(dir, hook)
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("no %s hook is installed in %s", hook, dir)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s hook: %v", hook, err)
+	}
+	if !strings.Contains(string(existing), hookMarker) {
+		return false, fmt.Errorf("the %s hook in %s was not installed by geminicommit, leaving it alone", hook, dir)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove %s hook: %v", hook, err)
+	}
+	backup := path + hookBackupSuffix
+	if _, err := os.Stat(backup); err != nil {
+		return false, nil
+	}
+	if err := os.Rename(backup, path); err != nil {
+		return false, fmt.Errorf("failed to restore previous %s hook: %v", hook, err)
+	}
+	return true, nil
+}
+
+// ShouldPrepareMessage reports whether prepare-commit-msg should generate a
+// message for the commit source git passes it. Only a plain `git commit`
+// (no source, or a commit.template) has no message yet; -m/-F, merges,
+// squashes and amends or -c/-C already come with one.
+func ShouldPrepareMessage(source string) bool {
+	return source == "" || source == "template"
+}
+
+// ShouldLintMessage reports whether the commit-msg hook should lint the
+// message. Commits made by gmc itself carry ValidatedMessageEnv and are let
+// through, so a message the user already confirmed is never rewritten.
+func ShouldLintMessage() bool {
+	return os.Getenv(ValidatedMessageEnv) == ""
+}
+
+// PrependMessageFile writes message at the top of the message file, above the
+// template and the comments git put there
+func PrependMessageFile(path string, message string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read commit message file: %v", err)
+	}
+
+	content := strings.TrimSpace(message) + "\n"
+	if len(existing) > 0 {
+		content += "\n" + strings.TrimLeft(string(existing), "\n")
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write commit message file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallHook(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, PrepareCommitMsgHook, "#!/bin/sh\necho mine\n")
+	script := HookScript(PrepareCommitMsgHook, "/usr/local/bin/gmc")
+
+	backup, err := InstallHook(dir, PrepareCommitMsgHook, script)
+	if err != nil {
+		t.Fatalf("InstallHook: %v", err)
+	}
+	if backup != filepath.Join(dir, PrepareCommitMsgHook+hookBackupSuffix) {
+		t.Fatalf("InstallHook backup = %q", backup)
+	}
+	installed, _ := os.ReadFile(filepath.Join(dir, PrepareCommitMsgHook))
+	if string(installed) != script {
+		t.Fatalf("installed hook = %q", installed)
+	}
+
+	// Reinstalling replaces geminicommit's own hook and keeps the backup
+	if backup, err := InstallHook(dir, PrepareCommitMsgHook, script); err != nil || backup != "" {
+		t.Fatalf("InstallHook again = %q, %v", backup, err)
+	}
+
+	restored, err := UninstallHook(dir, PrepareCommitMsgHook)
+	if err != nil || !restored {
+		t.Fatalf("UninstallHook = %v, %v", restored, err)
+	}
+	previous, _ := os.ReadFile(filepath.Join(dir, PrepareCommitMsgHook))
+	if string(previous) != "#!/bin/sh\necho mine\n" {
+		t.Fatalf("restored hook = %q", previous)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("backup still exists: %v", err)
+	}
+}
+
+func TestUninstallHookLeavesForeignHook(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := UninstallHook(dir, PrepareCommitMsgHook); err == nil {
+		t.Fatal("UninstallHook without a hook should fail")
+	}
+	writeFile(t, dir, PrepareCommitMsgHook, "#!/bin/sh\necho mine\n")
+	if _, err := UninstallHook(dir, PrepareCommitMsgHook); err == nil {
+		t.Fatal("UninstallHook should not remove a hook geminicommit didn't install")
+	}
+}
+
+func TestHookScriptQuotesExecutable(t *testing.T) {
+	script := HookScript(PrepareCommitMsgHook, "/home/o'neil/bin/gmc")
+	if !strings.Contains(script, `exec '/home/o'\''neil/bin/gmc' hook run --hook prepare-commit-msg "$@"`) {
+		t.Fatalf("HookScript() = %q", script)
+	}
+}
+
+func TestHookScriptFlags(t *testing.T) {
+	script := HookScript(CommitMsgHook, "/usr/local/bin/gmc", "--fix")
+	if !strings.Contains(script, `exec '/usr/local/bin/gmc' hook run --hook commit-msg --fix "$@"`) {
+		t.Fatalf("HookScript() = %q", script)
+	}
+	if !strings.Contains(script, `previous="$(dirname "$0")/commit-msg.pre-gmc"`) {
+		t.Fatalf("HookScript() does not chain to the previous hook: %q", script)
+	}
+}
+
+func TestShouldPrepareMessage(t *testing.T) {
+	for source, want := range map[string]bool{
+		"": true, "template": true, "message": false, "merge": false, "squash": false, "commit": false,
+	} {
+		if got := ShouldPrepareMessage(source); got != want {
+			t.Errorf("ShouldPrepareMessage(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestShouldLintMessage(t *testing.T) {
+	t.Setenv(ValidatedMessageEnv, "")
+	if !ShouldLintMessage() {
+		t.Fatal("ShouldLintMessage() = false for a plain git commit")
+	}
+	t.Setenv(ValidatedMessageEnv, "1")
+	if ShouldLintMessage() {
+		t.Fatal("ShouldLintMessage() = true for a commit made by gmc")
+	}
+}
+
+func TestPrependMessageFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "COMMIT_EDITMSG", "\n# Please enter the commit message\n")
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+
+	if err := PrependMessageFile(path, "fix: handle empty body\n"); err != nil {
+		t.Fatalf("PrependMessageFile: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "fix: handle empty body\n\n# Please enter the commit message\n"; string(got) != want {
+		t.Fatalf("message file = %q, want %q", got, want)
+	}
+}
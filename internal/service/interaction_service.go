@@ -19,6 +19,7 @@ const (
 	ActionEditContext Action = "EDIT_CONTEXT"
 	ActionCancel      Action = "CANCEL"
 	ActionAutoSelect  Action = "AUTO_SELECT"
+	ActionSkip        Action = "SKIP"
 )
 
 // InteractionService manages user interactions and UI
@@ -84,11 +85,17 @@ func (h *InteractionService) EditCommitMessage(originalMessage string) (string,
 	return message, nil
 }
 
-// EditContext allows the user to edit the user context
+// EditContext allows the user to edit the user context, including any attached
+// files and command output
 func (h *InteractionService) EditContext(userContext *string) error {
+	// Attached context can be far longer than anything typed in
+	charLimit := 1000
+	if len(*userContext) > charLimit {
+		charLimit = 0
+	}
 	if err := huh.NewForm(
 		huh.NewGroup(
-			huh.NewText().Title("Edit user context").CharLimit(1000).Value(userContext),
+			huh.NewText().Title("Edit user context").CharLimit(charLimit).Value(userContext),
 		),
 	).Run(); err != nil {
 		return err
@@ -123,6 +130,69 @@ func (h *InteractionService) DisplayDiff(diff string) {
 	fmt.Println()
 }
 
+// DisplayLintViolations warns the user about rules the commit message breaks
+func (h *InteractionService) DisplayLintViolations(violations []LintViolation, quiet *bool) {
+	if *quiet || len(violations) == 0 {
+		return
+	}
+
+	color.New(color.FgYellow).Println("⚠ The commit message breaks the repository's commit rules:")
+	for _, v := range violations {
+		color.New(color.FgYellow).Printf("  - %s\n", v)
+	}
+	fmt.Println()
+}
+
+// DisplayLintResult reports the outcome of linting one commit message. A
+// message with only warnings passes, with the warnings listed below it.
+func (h *InteractionService) DisplayLintResult(source string, message string, violations []LintViolation) {
+	header, _, _ := strings.Cut(message, "\n")
+	if len(LintErrors(violations)) == 0 {
+		color.New(color.FgGreen).Printf("✔ %s: %s\n", source, header)
+	} else {
+		color.New(color.FgRed).Printf("✖ %s: %s\n", source, header)
+	}
+	for _, v := range violations {
+		fmt.Printf("    %s\n", v)
+	}
+}
+
+// DisplaySecretFindings warns the user that the changes contain likely secrets
+func (h *InteractionService) DisplaySecretFindings(findings []SecretFinding) {
+	color.New(color.FgRed, color.Bold).Println("⚠ Possible secrets in the changes about to be committed:")
+	for _, f := range findings {
+		color.New(color.FgRed).Printf("  - %s\n", f)
+	}
+	color.New(color.FgYellow).Println("They are masked in the prompt, but would still be committed.")
+	fmt.Println()
+}
+
+// ConfirmSecrets asks whether to go on despite likely secrets in the changes
+func (h *InteractionService) ConfirmSecrets() (bool, error) {
+	proceed := false
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Continue anyway?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&proceed),
+		),
+	).Run(); err != nil {
+		return false, err
+	}
+
+	return proceed, nil
+}
+
+// DisplaySuggestedMessage shows an AI-corrected commit message
+func (h *InteractionService) DisplaySuggestedMessage(message string) {
+	color.New(color.FgCyan).Println("    Suggested message:")
+	for _, line := range strings.Split(message, "\n") {
+		fmt.Printf("      %s\n", line)
+	}
+}
+
 // ConfirmAutoSelectedFiles prompts the user to confirm, edit, or cancel AI-selected files
 func (h *InteractionService) ConfirmAutoSelectedFiles(files []string) (Action, []string, error) {
 	var choice string
@@ -200,4 +270,37 @@ func (h *InteractionService) EditFileList(files []string) ([]string, error) {
 	return selectedFiles, nil
 }
 
+// ReviewRewordedMessage shows the old and the generated message of a commit
+// and asks whether to use, edit or skip the new one, or cancel the reword
+func (h *InteractionService) ReviewRewordedMessage(hash string, oldMessage string, newMessage string) (Action, string, error) {
+	oldHeader, _, _ := strings.Cut(oldMessage, "\n")
+	color.New(color.FgYellow).Printf("\n%s ", hash)
+	color.New(color.Faint, color.CrossedOut).Println(oldHeader)
+	color.New(color.Bold).Printf("%s\n\n", newMessage)
+
+	var selectedAction Action
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[Action]().
+				Title("Use the new message?").
+				Options(
+					huh.NewOption("Yes", ActionConfirm),
+					huh.NewOption("Edit", ActionEdit),
+					huh.NewOption("Skip (keep the old message)", ActionSkip),
+					huh.NewOption("Cancel", ActionCancel),
+				).
+				Value(&selectedAction),
+		),
+	).Run(); err != nil {
+		return "", "", err
+	}
 
+	if selectedAction == ActionEdit {
+		editedMessage, err := h.EditCommitMessage(newMessage)
+		if err != nil {
+			return "", "", err
+		}
+		return ActionConfirm, editedMessage, nil
+	}
+	return selectedAction, newMessage, nil
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// IssueRef is an issue the commit refers to. Keyword, e.g. Closes, overrides
+// the configured footer keyword for this issue.
+type IssueRef struct {
+	ID      string
+	Keyword string
+}
+
+// Issue trackers, which decide how a detected issue is referenced
+const (
+	TrackerGitHub = "github"
+	TrackerGitLab = "gitlab"
+	TrackerJira   = "jira"
+	TrackerLinear = "linear"
+)
+
+// Where the issue reference goes in the commit message
+const (
+	IssuePlacementFooter = "footer"
+	IssuePlacementPrefix = "prefix"
+	IssuePlacementSuffix = "suffix"
+)
+
+var (
+	// closingKeyword optionally precedes an issue mentioned in the user context
+	closingKeyword = `(?:(?i:(close[sd]?|fix(?:e[sd])?|resolve[sd]?))\s+)?`
+	// contextNumberPattern and contextKeyPattern find issues mentioned in the
+	// user context, capturing the closing keyword (if any) and the issue
+	contextNumberPattern = regexp.MustCompile(closingKeyword + `#(\d+)\b`)
+	contextKeyPattern    = regexp.MustCompile(closingKeyword + `\b([A-Z][A-Z0-9]+-\d+)\b`)
+
+	// issueBranchPrefixes come before the issue number in names like fix-123
+	// or feature/123-login. A number after anything else is more likely part of
+	// a version or date, as in release-2024 or deps/bump-node-20.
+	issueBranchPrefixes = `issues?|gh|bug|bugfix|hotfix|fix|feat|feature|chore|docs|refactor`
+
+	// keyIssuePatterns find tracker keys like PROJ-123 or ENG-42. Lowercase keys
+	// (feature/proj-123-login) are only taken at the start of a path segment.
+	keyIssuePatterns = []string{
+		`(?:^|[/_-])(?P<key>[A-Z][A-Z0-9]+-\d+)\b`,
+		`(?i)(?:^|[/_])(?P<key>[a-z][a-z0-9]+-\d+)\b`,
+	}
+	// numberIssuePatterns find plain issue numbers: #123, a number after one of
+	// issueBranchPrefixes, or a number starting the name as in 123-login
+	numberIssuePatterns = []string{
+		`#(?P<number>\d+)`,
+		`(?i)(?:^|[/_-])(?:` + issueBranchPrefixes + `)[-/](?P<number>\d+)(?:[-_/]|$)`,
+		`^(?P<number>\d+)(?:-|$)`,
+	}
+	// notIssueKey matches words the default key patterns would otherwise take
+	// for a key, such as fix-123 or release-2024
+	notIssueKey = regexp.MustCompile(`(?i)^(?:` + issueBranchPrefixes + `|release|version|v)-`)
+)
+
+// IssueConfig controls how issues are detected in branch names and referenced
+type IssueConfig struct {
+	// Patterns are tried in order against the branch name. A named group "key"
+	// (uppercased, e.g. PROJ-123) or "number" holds the issue, otherwise the
+	// first group does. Empty means the defaults for Tracker.
+	Patterns []string
+	// Tracker is github, gitlab, jira, linear or empty for the generic behaviour
+	Tracker string
+	// URL is the tracker's base URL; when set the footer links to the issue
+	URL string
+	// Placement is footer (default), prefix or suffix
+	Placement string
+	// Fetch looks the issues up in the tracker to add their title and description to the prompt
+	Fetch bool
+	// Repo is the GitHub or GitLab repository issues are fetched from, as
+	// owner/repo or a clone URL; gmc fills it in from the origin remote
+	Repo string
+	// Token authenticates against the tracker's API; for Jira Cloud it is "email:api-token"
+	Token string
+	// APIURL overrides the API location derived from Repo, or for Jira from URL
+	APIURL string
+}
+
+// Validate reports unknown trackers and placements and invalid patterns
+func (c *IssueConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Tracker {
+	case "", TrackerGitHub, TrackerGitLab, TrackerJira, TrackerLinear:
+	default:
+		return fmt.Errorf("invalid issue tracker %q, expected github, gitlab, jira or linear", c.Tracker)
+	}
+	switch c.Placement {
+	case "", IssuePlacementFooter, IssuePlacementPrefix, IssuePlacementSuffix:
+	default:
+		return fmt.Errorf("invalid issue placement %q, expected footer, prefix or suffix", c.Placement)
+	}
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid issue pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// patterns returns the configured patterns or the defaults
+func (c *IssueConfig) patterns() []string {
+	if c != nil && len(c.Patterns) > 0 {
+		return c.Patterns
+	}
+	return append(append([]string{}, keyIssuePatterns...), numberIssuePatterns...)
+}
+
+// keeps reports whether a default pattern's match is kept: key-based trackers
+// only want keys and number-based ones only numbers. Keys are still matched
+// for number-based trackers so the 42 in PROJ-42 isn't taken for an issue.
+func (c *IssueConfig) keeps(key bool) bool {
+	if c == nil || len(c.Patterns) > 0 {
+		return true
+	}
+	switch c.Tracker {
+	case TrackerJira, TrackerLinear:
+		return key
+	case TrackerGitHub, TrackerGitLab:
+		return !key
+	}
+	return true
+}
+
+// ParseIssueRefs reads --issue values such as "12", "PROJ-4" or "closes:34"
+func ParseIssueRefs(values []string) []IssueRef {
+	var issues []IssueRef
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		ref := IssueRef{ID: value}
+		if keyword, id, ok := strings.Cut(value, ":"); ok && isKeyword(keyword) && !strings.HasPrefix(id, "//") {
+			ref = IssueRef{ID: strings.TrimSpace(id), Keyword: normalizeKeyword(keyword)}
+		}
+		ref.ID = strings.TrimPrefix(ref.ID, "#")
+		if ref.ID != "" && !strings.ContainsAny(ref.ID, "\r\n") {
+			issues = append(issues, ref)
+		}
+	}
+	return MergeIssueRefs(issues)
+}
+
+// MergeIssueRefs concatenates the lists, keeping the first mention of each
+// issue but the first keyword given for it
+func MergeIssueRefs(lists ...[]IssueRef) []IssueRef {
+	var merged []IssueRef
+	index := make(map[string]int)
+	for _, list := range lists {
+		for _, ref := range list {
+			if i, ok := index[ref.ID]; ok {
+				if merged[i].Keyword == "" {
+					merged[i].Keyword = ref.Keyword
+				}
+				continue
+			}
+			index[ref.ID] = len(merged)
+			merged = append(merged, ref)
+		}
+	}
+	return merged
+}
+
+func isKeyword(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeKeyword turns "closes", "closed" or "FIX" into trailer keywords like Closes and Fixes
+func normalizeKeyword(keyword string) string {
+	lower := strings.ToLower(keyword)
+	switch {
+	case strings.HasPrefix(lower, "close"):
+		return "Closes"
+	case strings.HasPrefix(lower, "fix"):
+		return "Fixes"
+	case strings.HasPrefix(lower, "resolve"):
+		return "Resolves"
+	}
+	return strings.ToUpper(lower[:1]) + lower[1:]
+}
+
+// DetectIssues returns every issue the branch name refers to, in order of
+// appearance. Patterns are tried in order and a match overlapping one found by
+// an earlier pattern is skipped, so PROJ-42 isn't also read as issue 42.
+func (c *IssueConfig) DetectIssues(branch string) []string {
+	type match struct {
+		start, end int
+		id         string
+		key        bool
+	}
+	var found []match
+	defaults := c == nil || len(c.Patterns) == 0
+
+	for _, pattern := range c.patterns() {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		group, upper := 1, false
+		if i := re.SubexpIndex("key"); i > 0 {
+			group, upper = i, true
+		} else if i := re.SubexpIndex("number"); i > 0 {
+			group = i
+		}
+		if re.NumSubexp() < group {
+			continue
+		}
+
+		for _, m := range re.FindAllStringSubmatchIndex(branch, -1) {
+			start, end := m[2*group], m[2*group+1]
+			if start < 0 {
+				continue
+			}
+			overlaps := false
+			for _, f := range found {
+				if start < f.end && f.start < end {
+					overlaps = true
+					break
+				}
+			}
+			if overlaps {
+				continue
+			}
+			id := branch[start:end]
+			if upper && defaults && notIssueKey.MatchString(id) {
+				continue
+			}
+			if upper {
+				id = strings.ToUpper(id)
+			}
+			found = append(found, match{start: start, end: end, id: id, key: upper})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+	var issues []string
+	seen := make(map[string]bool)
+	for _, f := range found {
+		if c.keeps(f.key) && !seen[f.id] {
+			seen[f.id] = true
+			issues = append(issues, f.id)
+		}
+	}
+	return issues
+}
+
+// DetectContextIssues finds issues mentioned in free text such as the user
+// context: #123 always, and keys like PROJ-123 for key-based trackers, where
+// words like UTF-8 can't be mistaken for them. A preceding "closes", "fixes"
+// or "resolves" becomes the issue's keyword.
+func (c *IssueConfig) DetectContextIssues(text string) []IssueRef {
+	patterns := []*regexp.Regexp{contextNumberPattern}
+	if c != nil && (c.Tracker == TrackerJira || c.Tracker == TrackerLinear) {
+		patterns = append(patterns, contextKeyPattern)
+	}
+
+	type match struct {
+		start int
+		ref   IssueRef
+	}
+	var found []match
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+			ref := IssueRef{ID: text[m[4]:m[5]]}
+			if m[2] >= 0 {
+				ref.Keyword = normalizeKeyword(text[m[2]:m[3]])
+			}
+			found = append(found, match{start: m[0], ref: ref})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+	issues := make([]IssueRef, 0, len(found))
+	for _, f := range found {
+		issues = append(issues, f.ref)
+	}
+	return MergeIssueRefs(issues)
+}
+
+// ShortRef formats an issue the way its tracker writes references: #123 for
+// numbers, keys such as PROJ-123 verbatim
+func (c *IssueConfig) ShortRef(issue string) string {
+	if isNumericID(issue) {
+		return "#" + issue
+	}
+	return issue
+}
+
+// FooterRef is the reference used in the footer: a link to the issue when the
+// tracker URL is configured, otherwise the short reference
+func (c *IssueConfig) FooterRef(issue string) string {
+	if c == nil || c.URL == "" || c.Tracker == "" {
+		return c.ShortRef(issue)
+	}
+	base := strings.TrimRight(c.URL, "/")
+	switch c.Tracker {
+	case TrackerGitHub:
+		return base + "/issues/" + issue
+	case TrackerGitLab:
+		return base + "/-/issues/" + issue
+	case TrackerJira:
+		return base + "/browse/" + issue
+	default:
+		return base + "/issue/" + issue
+	}
+}
+
+// AppendIssueRefs references the issues in the message according to Placement.
+// In the footer each is a "<keyword> #123" or "<keyword>: <key or url>" trailer; as a
+// prefix they start the subject's description, after the conventional
+// "type(scope): " header; as a suffix they follow the subject in parentheses,
+// like GitHub's squash merges. Issues with their own keyword, e.g. closes:34,
+// always go in the footer since the keyword only means something there.
+func (c *IssueConfig) AppendIssueRefs(message string, issues []IssueRef, keyword string) string {
+	placement := IssuePlacementFooter
+	if c != nil && c.Placement != "" {
+		placement = c.Placement
+	}
+
+	var footers, refs []string
+	for _, issue := range issues {
+		id := strings.TrimSpace(issue.ID)
+		if id == "" || strings.ContainsAny(id, "\r\n") {
+			continue
+		}
+		if placement != IssuePlacementFooter && issue.Keyword == "" {
+			refs = append(refs, c.ShortRef(id))
+			continue
+		}
+
+		footerKeyword := strings.TrimSpace(keyword)
+		if issue.Keyword != "" {
+			footerKeyword = issue.Keyword
+		}
+		if footerKeyword == "" || strings.ContainsAny(footerKeyword, "\r\n") {
+			continue
+		}
+		// Only "#123" parses as a trailer without a colon; keys and links need
+		// the "Token: value" form, or git and gmc lint take them for prose
+		ref := c.FooterRef(id)
+		footer := footerKeyword + ": " + ref
+		if strings.HasPrefix(ref, "#") {
+			footer = footerKeyword + " " + ref
+		}
+		footers = append(footers, footer)
+	}
+
+	if len(refs) > 0 {
+		message = addSubjectRefs(message, refs, placement)
+	}
+	return AppendTrailers(message, footers)
+}
+
+// addSubjectRefs puts the references missing from the subject at its start or end
+func addSubjectRefs(message string, refs []string, placement string) string {
+	subject, rest, multiline := strings.Cut(message, "\n")
+
+	var missing []string
+	for _, ref := range refs {
+		if !strings.Contains(subject, ref) {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) == 0 {
+		return message
+	}
+
+	joined := strings.Join(missing, ", ")
+	if placement == IssuePlacementSuffix {
+		subject = subject + " (" + joined + ")"
+	} else if m := headerPattern.FindStringSubmatchIndex(subject); m != nil {
+		subject = subject[:m[8]] + joined + " " + subject[m[8]:]
+	} else {
+		subject = joined + " " + subject
+	}
+
+	if !multiline {
+		return subject
+	}
+	return subject + "\n" + rest
+}
+
+func isNumericID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectIssues(t *testing.T) {
+	cases := []struct {
+		branch string
+		config *IssueConfig
+		want   []string
+	}{
+		{"feature/PROJ-42-login", nil, []string{"PROJ-42"}},
+		{"feature-123-description", nil, []string{"123"}},
+		{"fix-456-bug", nil, []string{"456"}},
+		{"#789-feature", nil, []string{"789"}},
+		{"issue-101", nil, []string{"101"}},
+		{"123-feature", nil, []string{"123"}},
+		{"release-1.2.3", nil, nil},
+		{"release/2.0-rc1", nil, nil},
+		{"release-2024", nil, nil},
+		{"release/2024-10-01", nil, nil},
+		{"deps/bump-node-20", nil, nil},
+		{"fix/123-foo", nil, []string{"123"}},
+		{"gh-12", nil, []string{"12"}},
+		{"feature/proj-123-login", nil, []string{"PROJ-123"}},
+		{"feature/proj-123-login", &IssueConfig{Tracker: TrackerJira}, []string{"PROJ-123"}},
+		{"FIX-456-bug", &IssueConfig{Tracker: TrackerJira}, nil},
+		{"main", nil, nil},
+		{"feature/PROJ-42-fix-456", &IssueConfig{Tracker: TrackerGitHub}, []string{"456"}},
+		{"feature/PROJ-42-fix-456", nil, []string{"PROJ-42", "456"}},
+		{"PROJ-1-PROJ-2-login", nil, []string{"PROJ-1", "PROJ-2"}},
+		{"feature-123-PROJ-42", &IssueConfig{Tracker: TrackerJira}, []string{"PROJ-42"}},
+		{"eng-7-login", &IssueConfig{Patterns: []string{`(?i)(?P<key>eng-\d+)`}}, []string{"ENG-7"}},
+		{"ticket_55", &IssueConfig{Patterns: []string{`ticket_(\d+)`}}, []string{"55"}},
+	}
+	for _, tc := range cases {
+		if got := tc.config.DetectIssues(tc.branch); !slices.Equal(got, tc.want) {
+			t.Errorf("DetectIssues(%q) = %q, want %q", tc.branch, got, tc.want)
+		}
+	}
+}
+
+func TestIssueConfigValidate(t *testing.T) {
+	for _, c := range []IssueConfig{{Tracker: "trello"}, {Placement: "body"}, {Patterns: []string{"("}}} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", c)
+		}
+	}
+	if err := (&IssueConfig{Tracker: TrackerLinear, Placement: IssuePlacementSuffix}).Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+}
+
+func TestAppendIssueRefs(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  *IssueConfig
+		message string
+		issues  []IssueRef
+		want    string
+	}{
+		{"default footer", nil, "feat: add login", []IssueRef{{ID: "12"}}, "feat: add login\n\nRefs #12"},
+		{"key footer", nil, "feat: add login", []IssueRef{{ID: "PROJ-4"}}, "feat: add login\n\nRefs: PROJ-4"},
+		{
+			"github link",
+			&IssueConfig{Tracker: TrackerGitHub, URL: "https://github.com/o/r/"},
+			"feat: add login", []IssueRef{{ID: "12"}},
+			"feat: add login\n\nRefs: https://github.com/o/r/issues/12",
+		},
+		{
+			"jira link",
+			&IssueConfig{Tracker: TrackerJira, URL: "https://x.atlassian.net"},
+			"feat: add login", []IssueRef{{ID: "PROJ-4"}},
+			"feat: add login\n\nRefs: https://x.atlassian.net/browse/PROJ-4",
+		},
+		{
+			"prefix after header",
+			&IssueConfig{Placement: IssuePlacementPrefix},
+			"feat(auth): add login\n\nBody.", []IssueRef{{ID: "PROJ-4"}},
+			"feat(auth): PROJ-4 add login\n\nBody.",
+		},
+		{
+			"suffix",
+			&IssueConfig{Placement: IssuePlacementSuffix},
+			"fix: handle nil", []IssueRef{{ID: "12"}},
+			"fix: handle nil (#12)",
+		},
+		{
+			"suffix is idempotent",
+			&IssueConfig{Placement: IssuePlacementSuffix},
+			"fix: handle nil (#12)", []IssueRef{{ID: "12"}},
+			"fix: handle nil (#12)",
+		},
+		{"no issue", nil, "fix: handle nil", nil, "fix: handle nil"},
+		{
+			"several with keywords",
+			nil,
+			"feat: add login", []IssueRef{{ID: "12"}, {ID: "34", Keyword: "Closes"}},
+			"feat: add login\n\nRefs #12\nCloses #34",
+		},
+		{
+			"keyword stays in footer",
+			&IssueConfig{Placement: IssuePlacementSuffix},
+			"feat: add login", []IssueRef{{ID: "12"}, {ID: "13"}, {ID: "34", Keyword: "Closes"}},
+			"feat: add login (#12, #13)\n\nCloses #34",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.config.AppendIssueRefs(tc.message, tc.issues, "Refs"); got != tc.want {
+				t.Fatalf("AppendIssueRefs() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAppendIssueRefs_footer(t *testing.T) {
+	cases := []struct {
+		name    string
+		message string
+		issue   string
+		keyword string
+		want    string
+	}{
+		{"numeric id", "feat(api): add profile endpoint", "128", "Closes", "feat(api): add profile endpoint\n\nCloses #128"},
+		{"jira key", "feat(api): add profile endpoint", "GEN-123", "Refs", "feat(api): add profile endpoint\n\nRefs: GEN-123"},
+		{"empty issue", "feat: add login", "", "Refs", "feat: add login"},
+		{"empty keyword", "feat: add login", "123", "", "feat: add login"},
+		{"newline in issue", "feat: add login", "12\n3", "Refs", "feat: add login"},
+		{"cr in issue", "feat: add login", "12\r3", "Refs", "feat: add login"},
+		{"newline in keyword", "feat: add login", "123", "Re\nfs", "feat: add login"},
+		{"cr in keyword", "feat: add login", "123", "Re\rfs", "feat: add login"},
+		{"idempotent", "feat(api): add profile endpoint\n\nRefs #128", "128", "Refs", "feat(api): add profile endpoint\n\nRefs #128"},
+		{
+			"idempotent only in the final paragraph",
+			"feat(api): mention Refs #128 in body\n\nmore detail", "128", "Refs",
+			"feat(api): mention Refs #128 in body\n\nmore detail\n\nRefs #128",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var config *IssueConfig
+			if got := config.AppendIssueRefs(tc.message, []IssueRef{{ID: tc.issue}}, tc.keyword); got != tc.want {
+				t.Fatalf("AppendIssueRefs() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseIssueRefs(t *testing.T) {
+	got := ParseIssueRefs([]string{"12", "closes:34", "PROJ-4", "#12", "Fixes: 56", "https://x/1", ""})
+	want := []IssueRef{{ID: "12"}, {ID: "34", Keyword: "Closes"}, {ID: "PROJ-4"}, {ID: "56", Keyword: "Fixes"}, {ID: "https://x/1"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseIssueRefs() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectContextIssues(t *testing.T) {
+	text := "Retry uploads (see #12). This closes #34 and fixes PROJ-7; UTF-8 only."
+
+	got := (*IssueConfig)(nil).DetectContextIssues(text)
+	want := []IssueRef{{ID: "12"}, {ID: "34", Keyword: "Closes"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("DetectContextIssues() = %v, want %v", got, want)
+	}
+
+	got = (&IssueConfig{Tracker: TrackerJira}).DetectContextIssues(text)
+	want = []IssueRef{{ID: "12"}, {ID: "34", Keyword: "Closes"}, {ID: "PROJ-7", Keyword: "Fixes"}, {ID: "UTF-8"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("DetectContextIssues() with jira = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxIssueDescription caps how much of an issue's description goes into the prompt
+const maxIssueDescription = 4000
+
+// IssueDetails is what the tracker says an issue is about
+type IssueDetails struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// String renders the issue as intent context for the prompt
+func (d IssueDetails) String() string {
+	text := fmt.Sprintf("Issue %s: %s", d.ID, d.Title)
+	description := strings.TrimSpace(d.Description)
+	if len(description) > maxIssueDescription {
+		// Cut before the rune the limit falls in, so the prompt stays valid UTF-8
+		cut := maxIssueDescription
+		for cut > 0 && !utf8.RuneStart(description[cut]) {
+			cut--
+		}
+		description = description[:cut] + "\n[... truncated]"
+	}
+	if description != "" {
+		text += "\n" + description
+	}
+	return text
+}
+
+// NewIssueHTTPClient returns the client used to query issue trackers
+func NewIssueHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// FetchIssue looks the issue up in the tracker's REST API. GitHub and GitLab
+// need Repo, Jira needs URL to point at the site. APIURL overrides the API
+// location, e.g. for GitHub Enterprise.
+func (c *IssueConfig) FetchIssue(ctx context.Context, client *http.Client, id string) (*IssueDetails, error) {
+	if c == nil {
+		return nil, fmt.Errorf("no issue tracker configured")
+	}
+
+	var endpoint string
+	header := http.Header{}
+	var decode func([]byte) (*IssueDetails, error)
+
+	switch c.Tracker {
+	case TrackerGitHub:
+		_, repo, err := repoLocation(c.Repo)
+		if err != nil {
+			return nil, err
+		}
+		api := c.APIURL
+		if api == "" {
+			api = "https://api.github.com"
+		}
+		endpoint = fmt.Sprintf("%s/repos/%s/issues/%s", strings.TrimRight(api, "/"), repo, url.PathEscape(id))
+		header.Set("Accept", "application/vnd.github+json")
+		if c.Token != "" {
+			header.Set("Authorization", "Bearer "+c.Token)
+		}
+		decode = func(body []byte) (*IssueDetails, error) {
+			var issue struct {
+				Title string `json:"title"`
+				Body  string `json:"body"`
+			}
+			err := json.Unmarshal(body, &issue)
+			return &IssueDetails{ID: id, Title: issue.Title, Description: issue.Body}, err
+		}
+	case TrackerGitLab:
+		host, project, err := repoLocation(c.Repo)
+		if err != nil {
+			return nil, err
+		}
+		api := c.APIURL
+		if api == "" {
+			if host == "" {
+				host = "gitlab.com"
+			}
+			api = "https://" + host + "/api/v4"
+		}
+		endpoint = fmt.Sprintf("%s/projects/%s/issues/%s", strings.TrimRight(api, "/"), url.PathEscape(project), url.PathEscape(id))
+		if c.Token != "" {
+			header.Set("PRIVATE-TOKEN", c.Token)
+		}
+		decode = func(body []byte) (*IssueDetails, error) {
+			var issue struct {
+				Title       string `json:"title"`
+				Description string `json:"description"`
+			}
+			err := json.Unmarshal(body, &issue)
+			return &IssueDetails{ID: id, Title: issue.Title, Description: issue.Description}, err
+		}
+	case TrackerJira:
+		api := c.APIURL
+		if api == "" {
+			if c.URL == "" {
+				return nil, fmt.Errorf("issue.url or issue.api_url must be set to fetch Jira issues")
+			}
+			api = strings.TrimRight(c.URL, "/") + "/rest/api/2"
+		}
+		endpoint = fmt.Sprintf("%s/issue/%s?fields=summary,description", strings.TrimRight(api, "/"), url.PathEscape(id))
+		// Jira Cloud takes "email:api-token" as basic auth, Data Center a bearer token
+		if strings.Contains(c.Token, ":") {
+			header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.Token)))
+		} else if c.Token != "" {
+			header.Set("Authorization", "Bearer "+c.Token)
+		}
+		decode = func(body []byte) (*IssueDetails, error) {
+			var issue struct {
+				Fields struct {
+					Summary     string `json:"summary"`
+					Description string `json:"description"`
+				} `json:"fields"`
+			}
+			err := json.Unmarshal(body, &issue)
+			return &IssueDetails{ID: id, Title: issue.Fields.Summary, Description: issue.Fields.Description}, err
+		}
+	default:
+		return nil, fmt.Errorf("fetching issues is not supported for tracker %q, expected github, gitlab or jira", c.Tracker)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issue request: %v", err)
+	}
+	req.Header = header
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue %s: %v", id, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue %s: %v", id, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch issue %s: %s", id, resp.Status)
+	}
+
+	details, err := decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issue %s: %v", id, err)
+	}
+	return details, nil
+}
+
+// repoLocation returns the host, if any, and the "owner/repo" or
+// "group/project" path of repo, which is either that path or a clone URL such
+// as https://github.com/owner/repo or git@gitlab.com:group/project.git
+func repoLocation(repo string) (string, string, error) {
+	repo = strings.TrimSpace(repo)
+	var host, path string
+	switch {
+	case strings.Contains(repo, "://"):
+		u, err := url.Parse(repo)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid issue.repo %q: %v", repo, err)
+		}
+		host, path = u.Hostname(), u.Path
+	case strings.Contains(repo, ":"):
+		// scp-like SSH remotes: git@host:owner/repo.git
+		host, path, _ = strings.Cut(repo, ":")
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	default:
+		path = repo
+	}
+
+	path = strings.Trim(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("set issue.repo to the repository, e.g. owner/repo, or add an origin remote")
+	}
+	return host, path, nil
+}
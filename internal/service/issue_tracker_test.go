@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFetchIssue(t *testing.T) {
+	cases := []struct {
+		name       string
+		config     IssueConfig
+		wantPath   string
+		wantHeader string
+		wantValue  string
+		response   string
+	}{
+		{
+			name:       "github",
+			config:     IssueConfig{Tracker: TrackerGitHub, Repo: "owner/repo", Token: "ghp_x"},
+			wantPath:   "/repos/owner/repo/issues/42",
+			wantHeader: "Authorization",
+			wantValue:  "Bearer ghp_x",
+			response:   `{"title": "Login fails", "body": "Steps to reproduce"}`,
+		},
+		{
+			name:       "gitlab",
+			config:     IssueConfig{Tracker: TrackerGitLab, Repo: "git@gitlab.com:group/project.git", Token: "glpat"},
+			wantPath:   "/projects/group%2Fproject/issues/42",
+			wantHeader: "Private-Token",
+			wantValue:  "glpat",
+			response:   `{"title": "Login fails", "description": "Steps to reproduce"}`,
+		},
+		{
+			name:       "jira cloud",
+			config:     IssueConfig{Tracker: TrackerJira, URL: "https://acme.atlassian.net", Token: "me@acme.com:tok"},
+			wantPath:   "/issue/42",
+			wantHeader: "Authorization",
+			wantValue:  "Basic " + base64.StdEncoding.EncodeToString([]byte("me@acme.com:tok")),
+			response:   `{"fields": {"summary": "Login fails", "description": "Steps to reproduce"}}`,
+		},
+		{
+			name:       "jira data center",
+			config:     IssueConfig{Tracker: TrackerJira, URL: "https://jira.acme.com", Token: "pat"},
+			wantPath:   "/issue/42",
+			wantHeader: "Authorization",
+			wantValue:  "Bearer pat",
+			response:   `{"fields": {"summary": "Login fails", "description": "Steps to reproduce"}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != tc.wantPath {
+					t.Errorf("path = %q, want %q", r.URL.EscapedPath(), tc.wantPath)
+				}
+				if got := r.Header.Get(tc.wantHeader); got != tc.wantValue {
+					t.Errorf("%s = %q, want %q", tc.wantHeader, got, tc.wantValue)
+				}
+				fmt.Fprint(w, tc.response)
+			}))
+			defer server.Close()
+
+			tc.config.APIURL = server.URL
+			details, err := tc.config.FetchIssue(context.Background(), server.Client(), "42")
+			if err != nil {
+				t.Fatalf("FetchIssue: %v", err)
+			}
+			if details.Title != "Login fails" || details.Description != "Steps to reproduce" {
+				t.Errorf("FetchIssue = %+v", details)
+			}
+		})
+	}
+}
+
+func TestFetchIssueErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := IssueConfig{Tracker: TrackerGitHub, Repo: "https://github.com/owner/repo", APIURL: server.URL}
+	if _, err := config.FetchIssue(context.Background(), server.Client(), "42"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("FetchIssue on 404 = %v, want status error", err)
+	}
+
+	config = IssueConfig{Tracker: TrackerGitHub, URL: "https://github.com/owner/repo", APIURL: server.URL}
+	if _, err := config.FetchIssue(context.Background(), server.Client(), "42"); err == nil {
+		t.Error("FetchIssue without a repository path should fail")
+	}
+
+	config = IssueConfig{Tracker: TrackerLinear, APIURL: server.URL}
+	if _, err := config.FetchIssue(context.Background(), server.Client(), "ENG-1"); err == nil {
+		t.Error("FetchIssue for linear should fail")
+	}
+}
+
+func TestRepoLocation(t *testing.T) {
+	cases := []struct {
+		repo, host, path string
+	}{
+		{"owner/repo", "", "owner/repo"},
+		{"https://github.com/owner/repo.git", "github.com", "owner/repo"},
+		{"git@gitlab.example.com:group/sub/project.git", "gitlab.example.com", "group/sub/project"},
+		{"ssh://git@gitlab.com:2222/group/project", "gitlab.com", "group/project"},
+	}
+	for _, tc := range cases {
+		host, path, err := repoLocation(tc.repo)
+		if err != nil || host != tc.host || path != tc.path {
+			t.Errorf("repoLocation(%q) = %q, %q, %v, want %q, %q", tc.repo, host, path, err, tc.host, tc.path)
+		}
+	}
+	if _, _, err := repoLocation("https://github.com"); err == nil {
+		t.Error("repoLocation without a path should fail")
+	}
+}
+
+func TestIssueDetailsString(t *testing.T) {
+	if got := (IssueDetails{ID: "42", Title: "Login fails"}).String(); got != "Issue 42: Login fails" {
+		t.Errorf("String() = %q", got)
+	}
+
+	long := IssueDetails{ID: "42", Title: "Login fails", Description: strings.Repeat("a", maxIssueDescription+10)}
+	got := long.String()
+	if !strings.HasSuffix(got, "[... truncated]") || len(got) > maxIssueDescription+100 {
+		t.Errorf("String() did not truncate the description, length %d", len(got))
+	}
+
+	// The limit falls inside a multi-byte character
+	wide := IssueDetails{ID: "42", Title: "Login fails", Description: "a" + strings.Repeat("é", maxIssueDescription)}
+	if got := wide.String(); !utf8.ValidString(got) || !strings.HasSuffix(got, "é\n[... truncated]") {
+		t.Errorf("String() cut a character in half: %q", got[len(got)-30:])
+	}
+}
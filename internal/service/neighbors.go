@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Which entries of the changed files' directories are listed as neighboring files
+const (
+	NeighborsOff     = "off"
+	NeighborsTracked = "tracked"
+	NeighborsAll     = "all"
+)
+
+// maxNeighbors caps the entries listed per directory so asset folders can't flood the prompt
+const maxNeighbors = 30
+
+// ValidNeighborsMode reports whether mode is one of the neighboring files modes
+func ValidNeighborsMode(mode string) bool {
+	switch mode {
+	case NeighborsOff, NeighborsTracked, NeighborsAll:
+		return true
+	}
+	return false
+}
+
+// getRelatedFiles lists the neighbors of the changed files, per directory,
+// ranked by relevance and capped at maxNeighbors
+func (g *GitService) getRelatedFiles(root string, files []string, mode string) map[string]string {
+	relatedFiles := make(map[string]string)
+	if mode == NeighborsOff {
+		return relatedFiles
+	}
+
+	changedByDir := make(map[string][]string)
+	for _, file := range files {
+		dir := path.Dir(filepath.ToSlash(file))
+		changedByDir[dir] = append(changedByDir[dir], path.Base(filepath.ToSlash(file)))
+	}
+
+	for dir, changed := range changedByDir {
+		var entries []string
+		var err error
+		if mode == NeighborsAll {
+			entries, err = readDirEntries(filepath.Join(root, dir))
+		} else {
+			entries, err = trackedDirEntries(root, dir)
+		}
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+		relatedFiles[dir] = strings.Join(rankNeighbors(entries, changed, maxNeighbors), ", ")
+	}
+
+	return relatedFiles
+}
+
+// readDirEntries lists everything in dir, subdirectories with a trailing slash
+func readDirEntries(dir string) ([]string, error) {
+	lsEntry, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]string, 0, len(lsEntry))
+	for _, entry := range lsEntry {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		entries = append(entries, name)
+	}
+	return entries, nil
+}
+
+// trackedDirEntries lists the tracked files directly in dir and the
+// subdirectories containing tracked files, with a trailing slash
+func trackedDirEntries(root string, dir string) ([]string, error) {
+	pathspec, prefix := ":(top,literal)"+dir, dir+"/"
+	if dir == "." {
+		pathspec, prefix = ":/", ""
+	}
+	output, err := exec.Command("git", "-C", root, "ls-files", "--full-name", "-z", "--", pathspec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files in %s: %v", dir, err)
+	}
+
+	seen := make(map[string]bool)
+	var entries []string
+	for _, file := range strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00") {
+		rest, ok := strings.CutPrefix(file, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		if sub, _, nested := strings.Cut(rest, "/"); nested {
+			rest = sub + "/"
+		}
+		if !seen[rest] {
+			seen[rest] = true
+			entries = append(entries, rest)
+		}
+	}
+	return entries, nil
+}
+
+// rankNeighbors orders entries by relevance to the changed files in the same
+// directory: their tests or sources first, then files with the same extension,
+// then the rest. Past limit the remainder is counted instead of listed.
+func rankNeighbors(entries []string, changed []string, limit int) []string {
+	stems := make(map[string]bool)
+	exts := make(map[string]bool)
+	for _, file := range changed {
+		stems[testStem(file)] = true
+		if ext := path.Ext(file); ext != "" {
+			exts[ext] = true
+		}
+	}
+
+	rank := func(entry string) int {
+		switch {
+		case strings.HasSuffix(entry, "/"):
+			return 3
+		case stems[testStem(entry)]:
+			return 0
+		case exts[path.Ext(entry)]:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	ranked := append([]string{}, entries...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ri, rj := rank(ranked[i]), rank(ranked[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		more := len(ranked) - limit
+		ranked = append(ranked[:limit], fmt.Sprintf("... and %d more", more))
+	}
+	return ranked
+}
+
+// testStem strips test markers so a source file and its test share a stem:
+// foo.go, foo_test.go, foo.test.ts, foo.spec.ts, test_foo.py all become "foo"
+func testStem(file string) string {
+	stem := strings.TrimSuffix(file, path.Ext(file))
+	for _, suffix := range []string{"_test", ".test", ".spec", "_spec", "Test"} {
+		stem = strings.TrimSuffix(stem, suffix)
+	}
+	return strings.TrimPrefix(stem, "test_")
+}
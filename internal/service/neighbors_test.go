@@ -0,0 +1,30 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRankNeighbors(t *testing.T) {
+	entries := []string{"README.md", "assets/", "b.go", "store.go", "store_test.go", "util.go", "z.txt"}
+
+	got := rankNeighbors(entries, []string{"store.go"}, 0)
+	want := []string{"store.go", "store_test.go", "b.go", "util.go", "README.md", "z.txt", "assets/"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("rankNeighbors() = %v, want %v", got, want)
+	}
+
+	got = rankNeighbors(entries, []string{"store.go"}, 3)
+	want = []string{"store.go", "store_test.go", "b.go", "... and 4 more"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("rankNeighbors() with limit = %v, want %v", got, want)
+	}
+}
+
+func TestTestStem(t *testing.T) {
+	for _, file := range []string{"foo.go", "foo_test.go", "foo.test.ts", "foo.spec.js", "test_foo.py", "fooTest.java"} {
+		if got := testStem(file); got != "foo" {
+			t.Errorf("testStem(%q) = %q, want foo", file, got)
+		}
+	}
+}
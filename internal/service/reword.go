@@ -0,0 +1,182 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RewordBackupRef points at the branch tip from before the last gmc reword
+const RewordBackupRef = "refs/gmc/reword-backup"
+
+// CheckRewordRange makes sure the commits of revisionRange can be rewritten
+// without a real rebase: there must be some, none of them a merge, and the
+// newest one must be HEAD so moving the branch doesn't drop later commits.
+func (g *GitService) CheckRewordRange(revisionRange string, commits []CommitMessage) error {
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits in '%s'", revisionRange)
+	}
+
+	merges, err := exec.Command("git", "rev-list", "--merges", revisionRange, "--").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list merges in '%s': %v", revisionRange, err)
+	}
+	if strings.TrimSpace(string(merges)) != "" {
+		return fmt.Errorf("'%s' contains merge commits, which can't be reworded", revisionRange)
+	}
+
+	head, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %v", err)
+	}
+	if commits[len(commits)-1].Hash != strings.TrimSpace(string(head)) {
+		return fmt.Errorf("'%s' must end at HEAD, e.g. origin/main..HEAD", revisionRange)
+	}
+	return nil
+}
+
+// HasPushedCommits reports whether any of the commits of revisionRange is on a
+// remote-tracking branch, so rewording the range would rewrite published history
+func (g *GitService) HasPushedCommits(revisionRange string, commits []CommitMessage) (bool, error) {
+	output, err := exec.Command("git", "rev-list", revisionRange, "--not", "--remotes", "--").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether '%s' was pushed: %v", revisionRange, err)
+	}
+	unpushed := map[string]bool{}
+	for _, hash := range strings.Fields(string(output)) {
+		unpushed[hash] = true
+	}
+	for _, c := range commits {
+		if !unpushed[c.Hash] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CommitChanges lists the files a single commit changes, compared with its
+// parent or, for the first commit, the empty tree
+func (g *GitService) CommitChanges(hash string) (*ChangeSet, error) {
+	parent := emptyTree
+	if output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", hash+"^").Output(); err == nil {
+		parent = strings.TrimSpace(string(output))
+	}
+
+	set, err := g.changesBetween(parent, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the changes of %s: %v", shortHash(hash), err)
+	}
+	return set, nil
+}
+
+// PrepareCommitData builds what is sent for a single commit the same way as for
+// staged changes: its diff, or in the stricter privacy modes a description of
+// it, with the dependency, Go API and small file summaries
+func (g *GitService) PrepareCommitData(hash string, diffOpts *DiffOptions, privacy string) (*PreCommitData, error) {
+	set, err := g.CommitChanges(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(append([]string{"diff", "--no-color"}, diffOpts.gitArgs()...), set.diffArgs...)
+	diff, err := exec.Command("git", append(args, diffOpts.diffPathspecArgs()...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff of %s: %v", shortHash(hash), err)
+	}
+	stubs, err := g.excludedDiffStubs(diffOpts, set.diffArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(set.Changes))
+	for _, c := range set.Changes {
+		files = append(files, c.Path)
+	}
+	data := &PreCommitData{
+		Files:        files,
+		Diff:         joinDiff(summarizeGeneratedDiffs(string(diff)), stubs),
+		RelatedFiles: map[string]string{},
+		Privacy:      privacy,
+	}
+	if err := g.summarizeChanges(data, set, diffOpts); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RewordCommits recreates commits, oldest first, with the messages in
+// messages (keyed by hash), keeping each commit's tree, author and author
+// date. Commits before the first changed message are left as they are. The
+// branch is moved to the new tip, and the old tip is kept in RewordBackupRef.
+func (g *GitService) RewordCommits(commits []CommitMessage, messages map[string]string) (string, error) {
+	rewritten := make(map[string]string, len(commits))
+	tip := ""
+	for _, c := range commits {
+		output, err := exec.Command(
+			"git", "log", "-1", "--date=raw", "--format=%T%x00%P%x00%an%x00%ae%x00%ad", c.Hash,
+		).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to read commit %s: %v", shortHash(c.Hash), err)
+		}
+		fields := strings.Split(strings.TrimSpace(string(output)), "\x00")
+		if len(fields) != 5 {
+			return "", fmt.Errorf("unexpected metadata for commit %s", shortHash(c.Hash))
+		}
+		tree, parents := fields[0], strings.Fields(fields[1])
+		if len(parents) > 1 {
+			return "", fmt.Errorf("commit %s is a merge, which can't be reworded", shortHash(c.Hash))
+		}
+
+		parent := ""
+		if len(parents) == 1 {
+			parent = parents[0]
+			if p, ok := rewritten[parent]; ok {
+				parent = p
+			}
+		}
+		message, changed := messages[c.Hash]
+		if !changed && (len(parents) == 0 || parent == parents[0]) {
+			// Nothing to change, so the commit and its signature stay as they are
+			rewritten[c.Hash] = c.Hash
+			tip = c.Hash
+			continue
+		}
+		if !changed {
+			message = c.Message
+		}
+
+		args := []string{"commit-tree", tree, "-F", "-"}
+		if parent != "" {
+			args = append(args, "-p", parent)
+		}
+		cmd := exec.Command("git", args...)
+		cmd.Stdin = strings.NewReader(strings.TrimSpace(message) + "\n")
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+fields[2],
+			"GIT_AUTHOR_EMAIL="+fields[3],
+			"GIT_AUTHOR_DATE="+fields[4],
+		)
+		hash, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to rewrite commit %s: %v", shortHash(c.Hash), err)
+		}
+		rewritten[c.Hash] = strings.TrimSpace(string(hash))
+		tip = rewritten[c.Hash]
+	}
+
+	oldTip := commits[len(commits)-1].Hash
+	if err := exec.Command("git", "update-ref", "-m", "gmc reword: backup", RewordBackupRef, oldTip).Run(); err != nil {
+		return "", fmt.Errorf("failed to save backup ref: %v", err)
+	}
+	// Passing the old tip makes git refuse if HEAD moved in the meantime
+	if err := exec.Command("git", "update-ref", "-m", "gmc reword", "HEAD", tip, oldTip).Run(); err != nil {
+		return "", fmt.Errorf("failed to move HEAD to the reworded commits: %v", err)
+	}
+	return tip, nil
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	return hash[:min(len(hash), 12)]
+}
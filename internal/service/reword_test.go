@@ -0,0 +1,223 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// testRepo creates a repository in a temporary directory and makes it the
+// working directory. The returned function runs git in it and returns the
+// trimmed output.
+func testRepo(t *testing.T) func(args ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// Keep the user's config (signing, hooks, templates) out of the test
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	for _, role := range []string{"AUTHOR", "COMMITTER"} {
+		t.Setenv("GIT_"+role+"_NAME", "Committer")
+		t.Setenv("GIT_"+role+"_EMAIL", "committer@example.com")
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q", "-b", "main")
+	return git
+}
+
+// commitFile writes content to file and commits it as author at date
+func commitFile(t *testing.T, git func(args ...string) string, file, content, message, author, date string) string {
+	t.Helper()
+	writeFile(t, ".", file, content)
+	git("add", file)
+	t.Setenv("GIT_AUTHOR_DATE", date)
+	git("commit", "-q", "--author", author, "-m", message)
+	return git("rev-parse", "HEAD")
+}
+
+func TestRewordCommits(t *testing.T) {
+	git := testRepo(t)
+	base := commitFile(t, git, "a.txt", "a\n", "init", "Ann <ann@example.com>", "1700000000 +0100")
+	kept := commitFile(t, git, "b.txt", "b\n", "feat: add b", "Bob <bob@example.com>", "1700000100 +0200")
+	reworded := commitFile(t, git, "c.txt", "c\n", "wip", "Cat <cat@example.com>", "1700000200 -0500")
+	skipped := commitFile(t, git, "c.txt", "c2\n", "fixup! wip", "Dan <dan@example.com>", "1700000300 +0000")
+
+	commits, err := NewGitService().GetCommitMessages(base + "..HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitMessages: %v", err)
+	}
+	tip, err := NewGitService().RewordCommits(commits, map[string]string{reworded: "feat: add c\n\nRefs: #12"})
+	if err != nil {
+		t.Fatalf("RewordCommits: %v", err)
+	}
+
+	if head := git("rev-parse", "HEAD"); head != tip {
+		t.Fatalf("HEAD = %s, want the new tip %s", head, tip)
+	}
+	if backup := git("rev-parse", RewordBackupRef); backup != skipped {
+		t.Fatalf("%s = %s, want the old tip %s", RewordBackupRef, backup, skipped)
+	}
+	if status := git("status", "--porcelain"); status != "" {
+		t.Fatalf("worktree changed by the reword:\n%s", status)
+	}
+
+	// Commits before the first changed message are not rewritten
+	if got := git("rev-parse", "HEAD~3"); got != base {
+		t.Fatalf("HEAD~3 = %s, want %s", got, base)
+	}
+	if got := git("rev-parse", "HEAD~2"); got != kept {
+		t.Fatalf("HEAD~2 = %s, want %s", got, kept)
+	}
+
+	format := "--format=%T%n%an <%ae>%n%ad"
+	for rev, old := range map[string]string{"HEAD~1": reworded, "HEAD": skipped} {
+		if git("rev-parse", rev) == old {
+			t.Fatalf("%s was not rewritten onto the reworded parent", rev)
+		}
+		if got, want := git("log", "-1", "--date=raw", format, rev), git("log", "-1", "--date=raw", format, old); got != want {
+			t.Errorf("%s tree, author or date = %q, want %q", rev, got, want)
+		}
+	}
+
+	if got := git("log", "-1", "--format=%B", "HEAD~1"); got != "feat: add c\n\nRefs: #12" {
+		t.Errorf("reworded message = %q", got)
+	}
+	if got := git("log", "-1", "--format=%B", "HEAD"); got != "fixup! wip" {
+		t.Errorf("skipped message = %q, want it unchanged", got)
+	}
+}
+
+func TestRewordCommits_skewedDates(t *testing.T) {
+	git := testRepo(t)
+	base := commitFile(t, git, "a.txt", "a\n", "init", "Ann <ann@example.com>", "1700000000 +0000")
+	// Each commit claims to be older than its parent, as after a rebase on a
+	// machine with a wrong clock
+	var hashes []string
+	for i, date := range []string{"1600000300 +0000", "1600000200 +0000", "1600000100 +0000"} {
+		t.Setenv("GIT_COMMITTER_DATE", date)
+		hashes = append(hashes, commitFile(t, git, "b.txt", strings.Repeat("b\n", i+1), "wip", "Ann <ann@example.com>", date))
+	}
+
+	commits, err := NewGitService().GetCommitMessages(base + "..HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitMessages: %v", err)
+	}
+	for i, c := range commits {
+		if c.Hash != hashes[i] {
+			t.Fatalf("commit %d = %s, want %s (parents first)", i, c.Hash, hashes[i])
+		}
+	}
+
+	messages := map[string]string{}
+	for i, hash := range hashes {
+		messages[hash] = fmt.Sprintf("feat: step %d", i+1)
+	}
+	if _, err := NewGitService().RewordCommits(commits, messages); err != nil {
+		t.Fatalf("RewordCommits: %v", err)
+	}
+	// The new chain must not point back at any of the old commits
+	if got := git("log", "--format=%s", base+"..HEAD"); got != "feat: step 3\nfeat: step 2\nfeat: step 1" {
+		t.Fatalf("reworded history = %q", got)
+	}
+	if got := git("rev-parse", "HEAD~3"); got != base {
+		t.Fatalf("HEAD~3 = %s, want %s", got, base)
+	}
+}
+
+func TestCheckRewordRange(t *testing.T) {
+	git := testRepo(t)
+	base := commitFile(t, git, "a.txt", "a\n", "init", "Ann <ann@example.com>", "1700000000 +0000")
+	commitFile(t, git, "b.txt", "b\n", "feat: add b", "Ann <ann@example.com>", "1700000100 +0000")
+	g := NewGitService()
+
+	commits, _ := g.GetCommitMessages(base + "..HEAD")
+	if err := g.CheckRewordRange(base+"..HEAD", commits); err != nil {
+		t.Fatalf("CheckRewordRange(linear) = %v", err)
+	}
+
+	// A range that stops before HEAD would drop the later commits
+	commits, _ = g.GetCommitMessages(base + ".." + base)
+	if err := g.CheckRewordRange(base+".."+base, commits); err == nil {
+		t.Fatal("CheckRewordRange(empty) = nil, want error")
+	}
+	commits, _ = g.GetCommitMessages("HEAD~1")
+	if err := g.CheckRewordRange("HEAD~1", commits); err == nil {
+		t.Fatal("CheckRewordRange(not ending at HEAD) = nil, want error")
+	}
+
+	git("checkout", "-q", "-b", "topic", base)
+	commitFile(t, git, "c.txt", "c\n", "feat: add c", "Ann <ann@example.com>", "1700000200 +0000")
+	git("checkout", "-q", "main")
+	git("merge", "-q", "--no-ff", "-m", "merge topic", "topic")
+
+	commits, _ = g.GetCommitMessages(base + "..HEAD")
+	if err := g.CheckRewordRange(base+"..HEAD", commits); err == nil || !strings.Contains(err.Error(), "merge") {
+		t.Fatalf("CheckRewordRange(with merge) = %v, want merge error", err)
+	}
+}
+
+func TestHasPushedCommits(t *testing.T) {
+	git := testRepo(t)
+	base := commitFile(t, git, "a.txt", "a\n", "init", "Ann <ann@example.com>", "1700000000 +0000")
+	pushed := commitFile(t, git, "b.txt", "b\n", "feat: add b", "Ann <ann@example.com>", "1700000100 +0000")
+	commitFile(t, git, "c.txt", "c\n", "feat: add c", "Ann <ann@example.com>", "1700000200 +0000")
+	git("update-ref", "refs/remotes/origin/main", pushed)
+	g := NewGitService()
+
+	for _, tc := range []struct {
+		revisionRange string
+		want          bool
+	}{
+		{base + "..HEAD", true},
+		{pushed + "..HEAD", false},
+	} {
+		commits, _ := g.GetCommitMessages(tc.revisionRange)
+		got, err := g.HasPushedCommits(tc.revisionRange, commits)
+		if err != nil || got != tc.want {
+			t.Errorf("HasPushedCommits(%s) = %v, %v, want %v", tc.revisionRange, got, err, tc.want)
+		}
+	}
+}
+
+func TestPrepareCommitData_Privacy(t *testing.T) {
+	git := testRepo(t)
+	commitFile(t, git, "main.go", "package main\n", "init", "Ann <ann@example.com>", "1700000000 +0000")
+	hash := commitFile(t, git, "main.go", "package main\n\nfunc secretAlgorithm() {}\n", "wip", "Ann <ann@example.com>", "1700000100 +0000")
+	g := NewGitService()
+
+	full, err := g.PrepareCommitData(hash, &DiffOptions{}, PrivacyFull)
+	if err != nil {
+		t.Fatalf("PrepareCommitData(full): %v", err)
+	}
+	if !strings.Contains(full.Diff, "+func secretAlgorithm() {}") || len(full.Files) != 1 {
+		t.Fatalf("PrepareCommitData(full) = %+v", full)
+	}
+
+	for _, privacy := range []string{PrivacySymbols, PrivacyStats} {
+		data, err := g.PrepareCommitData(hash, &DiffOptions{}, privacy)
+		if err != nil {
+			t.Fatalf("PrepareCommitData(%s): %v", privacy, err)
+		}
+		if strings.Contains(data.PromptDiff(), "func secretAlgorithm() {}") || !strings.Contains(data.Diff, "M  main.go  +2 -0") {
+			t.Errorf("PrepareCommitData(%s) sent code:\n%s", privacy, data.PromptDiff())
+		}
+	}
+
+	// The first commit is compared with the empty tree
+	first := git("rev-list", "--max-parents=0", "HEAD")
+	data, err := g.PrepareCommitData(first, &DiffOptions{}, PrivacyStats)
+	if err != nil || !strings.Contains(data.Diff, "A  main.go") {
+		t.Fatalf("PrepareCommitData(root) = %+v, %v", data, err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// packageManifests mark the root of a package whose directory name is used as scope
+var packageManifests = []string{"go.mod", "package.json", "Cargo.toml"}
+
+// ResolveScopes maps the changed files to commit scopes. Globs from the [scopes]
+// config table take precedence (most specific pattern first); otherwise the name of
+// the deepest package root containing the file is used. Files matching neither are
+// ignored. The result is sorted and free of duplicates.
+func ResolveScopes(files []string, globs map[string]string, packageRoots []string) []string {
+	patterns := make([]string, 0, len(globs))
+	for pattern := range globs {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	roots := slices.Clone(packageRoots)
+	sort.Slice(roots, func(i, j int) bool { return len(roots[i]) > len(roots[j]) })
+
+	var scopes []string
+	for _, file := range files {
+		scope := ""
+		// viper lowercases config keys, so globs are matched case-insensitively
+		lower := strings.ToLower(file)
+		for _, pattern := range patterns {
+			if MatchPathGlob(strings.ToLower(pattern), lower) {
+				scope = globs[pattern]
+				break
+			}
+		}
+		if scope == "" {
+			for _, root := range roots {
+				if strings.HasPrefix(file, root+"/") {
+					scope = path.Base(root)
+					break
+				}
+			}
+		}
+		if scope != "" && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	sort.Strings(scopes)
+	return scopes
+}
+
+// PackageRoots returns the directories holding a package manifest, skipping the
+// repository root itself since it doesn't make a useful scope.
+func PackageRoots(trackedManifests []string) []string {
+	var roots []string
+	for _, manifest := range trackedManifests {
+		if !slices.Contains(packageManifests, path.Base(manifest)) {
+			continue
+		}
+		dir := path.Dir(manifest)
+		if dir != "." && !slices.Contains(roots, dir) {
+			roots = append(roots, dir)
+		}
+	}
+	return roots
+}
+
+// MatchPathGlob reports whether the slash-separated name matches pattern. `**`
+// matches any number of directories, a pattern without a slash matches the file
+// name at any depth, and a pattern matching a directory matches everything below it.
+func MatchPathGlob(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+
+	patternParts := strings.Split(pattern, "/")
+	nameParts := strings.Split(name, "/")
+	for i := 1; i <= len(nameParts); i++ {
+		if matchGlobSegments(patternParts, nameParts[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlobSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchGlobSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "internal/service/**", name: "internal/service/git_service.go", want: true},
+		{pattern: "internal/service/**", name: "internal/usecase/root_usecase.go", want: false},
+		{pattern: "internal/service", name: "internal/service/sub/x.go", want: true},
+		{pattern: "*.md", name: "docs/guide/intro.md", want: true},
+		{pattern: "cmd/*.go", name: "cmd/config/get.go", want: false},
+		{pattern: "**/testdata/**", name: "a/b/testdata/x.json", want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pattern+"/"+tc.name, func(t *testing.T) {
+			if got := MatchPathGlob(tc.pattern, tc.name); got != tc.want {
+				t.Fatalf("MatchPathGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveScopes_configWins(t *testing.T) {
+	globs := map[string]string{
+		"internal/**":         "internal",
+		"internal/service/**": "service",
+	}
+	got := ResolveScopes(
+		[]string{"internal/service/a.go", "internal/service/b.go"},
+		globs,
+		[]string{"internal"},
+	)
+	if !slices.Equal(got, []string{"service"}) {
+		t.Fatalf("ResolveScopes() = %v, want [service]", got)
+	}
+}
+
+func TestResolveScopes_packageRoots(t *testing.T) {
+	got := ResolveScopes(
+		[]string{"apps/web/src/index.ts", "packages/ui/button.tsx", "README.md", "apps/web/package.json"},
+		nil,
+		[]string{"apps/web", "packages/ui"},
+	)
+	if !slices.Equal(got, []string{"ui", "web"}) {
+		t.Fatalf("ResolveScopes() = %v, want [ui web]", got)
+	}
+}
+
+func TestPackageRoots(t *testing.T) {
+	got := PackageRoots([]string{"go.mod", "tools/go.mod", "web/package.json", "web/package.json", "notes.txt"})
+	if !slices.Equal(got, []string{"tools", "web"}) {
+		t.Fatalf("PackageRoots() = %v, want [tools web]", got)
+	}
+}
+
+func TestLintRules_WithScopes(t *testing.T) {
+	rules := DefaultLintRules(72, 72).WithScopes([]string{"service"})
+	if !rules.ScopeRequired {
+		t.Fatal("ScopeRequired = false, want true")
+	}
+	got := lintRuleIDs(LintCommitMessage("fix(svc): handle empty body", rules))
+	if !slices.Equal(got, []string{"scope-enum"}) {
+		t.Fatalf("LintCommitMessage() rules = %v, want [scope-enum]", got)
+	}
+	got = lintRuleIDs(LintCommitMessage("fix: handle empty body", rules))
+	if !slices.Equal(got, []string{"scope-empty"}) {
+		t.Fatalf("LintCommitMessage() rules = %v, want [scope-empty]", got)
+	}
+}
+
+func TestLintRules_WithScopesRespectsScopeEnum(t *testing.T) {
+	rules := &LintRules{Scopes: []string{"api", "cli"}}
+	if got := rules.WithScopes([]string{"service"}); got != rules {
+		t.Fatalf("WithScopes() = %+v, want rules unchanged", got)
+	}
+}
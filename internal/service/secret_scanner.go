@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// What to do when the diff contains something that looks like a secret
+const (
+	SecretActionWarn  = "warn"
+	SecretActionAbort = "abort"
+	SecretActionOff   = "off"
+)
+
+// SecretRule detects one kind of secret. When Pattern has a capture group only
+// the group is masked, so the surrounding key name stays readable for the AI.
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// MinEntropy, when set, also requires the masked value to look random
+	MinEntropy float64
+}
+
+// SecretFinding is a rule that matched a line added in File
+type SecretFinding struct {
+	Rule string
+	File string
+}
+
+func (f SecretFinding) String() string {
+	return fmt.Sprintf("%s in %s", f.Rule, f.File)
+}
+
+// DefaultSecretRules returns the built-in ruleset
+func DefaultSecretRules() []SecretRule {
+	return []SecretRule{
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:(.*?))-----END [A-Z ]*PRIVATE KEY-----`)},
+		{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+		{Name: "aws-secret-key", Pattern: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?['"]?\s*[:=]+\s*['"]?([A-Za-z0-9/+=]{40})\b`)},
+		{Name: "gcp-service-account", Pattern: regexp.MustCompile(`"private_key_id"\s*:\s*"([0-9a-f]{40})"`)},
+		{Name: "gcp-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+		{
+			Name:       "high-entropy-string",
+			Pattern:    regexp.MustCompile(`(?i)(?:key|secret|token|passw(?:or)?d|pwd|credential|auth)[\w.-]*['"]?\s*(?::=|[:=])\s*['"]?([A-Za-z0-9+/_.~=-]{16,})`),
+			MinEntropy: 3.5,
+		},
+	}
+}
+
+// SecretRules returns the built-in rules followed by one "custom" rule per user pattern
+func SecretRules(patterns []string) ([]SecretRule, error) {
+	rules := DefaultSecretRules()
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return rules, fmt.Errorf("invalid secret pattern %q: %v", pattern, err)
+		}
+		rules = append(rules, SecretRule{Name: "custom", Pattern: re})
+	}
+	return rules, nil
+}
+
+// RedactSecrets masks every secret in diff as [REDACTED:<rule>] and reports the
+// ones on added lines, i.e. those that are about to be committed
+func RedactSecrets(diff string, rules []SecretRule) (string, []SecretFinding) {
+	if len(rules) == 0 {
+		return diff, nil
+	}
+
+	var findings []SecretFinding
+	seen := map[SecretFinding]bool{}
+	files := splitDiff(diff)
+	redacted := false
+
+	for i, f := range files {
+		text := f.Text
+		for _, rule := range rules {
+			var masked bool
+			var added bool
+			text, masked, added = redactRule(text, rule)
+			if !masked {
+				continue
+			}
+			redacted = true
+			finding := SecretFinding{Rule: rule.Name, File: f.Path}
+			if added && !seen[finding] {
+				seen[finding] = true
+				findings = append(findings, finding)
+			}
+		}
+		files[i].Text = text
+	}
+
+	if !redacted {
+		return diff, nil
+	}
+	return joinFileDiffs(files), findings
+}
+
+// RedactFileContents masks secrets in file contents the same way. They are not
+// reported: a secret that is new in this commit also shows up in the diff.
+func RedactFileContents(files []FileContent, rules []SecretRule) {
+	for i := range files {
+		files[i].Content = RedactText(files[i].Content, rules)
+	}
+}
+
+// RedactText masks secrets in text that isn't a diff, such as attached context
+func RedactText(text string, rules []SecretRule) string {
+	for _, rule := range rules {
+		text, _, _ = redactRule(text, rule)
+	}
+	return text
+}
+
+// redactRule masks the matches of one rule, reporting whether any was masked
+// and whether any sat on an added line
+func redactRule(text string, rule SecretRule) (string, bool, bool) {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, false, false
+	}
+
+	var b strings.Builder
+	last := 0
+	masked, added := false, false
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if rule.MinEntropy > 0 && !looksRandom(text[start:end], rule.MinEntropy) {
+			continue
+		}
+
+		lineStart := strings.LastIndexByte(text[:m[0]], '\n') + 1
+		if strings.HasPrefix(text[lineStart:], "+") && !strings.HasPrefix(text[lineStart:], "+++") {
+			added = true
+		}
+
+		b.WriteString(text[last:start])
+		b.WriteString("[REDACTED:" + rule.Name + "]")
+		last = end
+		masked = true
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), masked, added
+}
+
+// looksRandom reports whether s mixes letters and digits with at least minEntropy
+// bits of Shannon entropy per character, which rules out ordinary identifiers
+func looksRandom(s string, minEntropy float64) bool {
+	hasLetter := strings.IndexFunc(s, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(s, unicode.IsDigit) >= 0
+	return hasLetter && hasDigit && shannonEntropy(s) >= minEntropy
+}
+
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Symbol is a top-level declaration and the lines it spans
+type Symbol struct {
+	Kind  string
+	Name  string
+	Start int
+	End   int
+}
+
+func (s Symbol) String() string {
+	return s.Kind + " " + s.Name
+}
+
+// SymbolChanges lists the declarations a change added, removed or modified
+type SymbolChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+type symbolPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var (
+	pythonSymbols = []symbolPattern{
+		{"func", regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^\s*class\s+(\w+)`)},
+	}
+	scriptSymbols = []symbolPattern{
+		{"func", regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+(\w+)`)},
+		{"func", regexp.MustCompile(`^\s*(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s*)?(?:\([^)]*\)|\w+)\s*=>`)},
+		{"class", regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)`)},
+		{"interface", regexp.MustCompile(`^\s*(?:export\s+)?interface\s+(\w+)`)},
+		{"type", regexp.MustCompile(`^\s*(?:export\s+)?type\s+(\w+)\s*(?:<[^>]*>)?\s*=`)},
+	}
+	rustSymbols = []symbolPattern{
+		{"fn", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+(\w+)`)},
+		{"struct", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?struct\s+(\w+)`)},
+		{"enum", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?enum\s+(\w+)`)},
+		{"trait", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?trait\s+(\w+)`)},
+	}
+	rubySymbols = []symbolPattern{
+		{"def", regexp.MustCompile(`^\s*def\s+(?:self\.)?(\w+[?!]?)`)},
+		{"class", regexp.MustCompile(`^\s*class\s+(\w+)`)},
+		{"module", regexp.MustCompile(`^\s*module\s+(\w+)`)},
+	}
+	phpSymbols = []symbolPattern{
+		{"function", regexp.MustCompile(`^\s*(?:(?:public|protected|private|static|abstract|final)\s+)*function\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^\s*(?:(?:abstract|final)\s+)?class\s+(\w+)`)},
+	}
+	jvmSymbols = []symbolPattern{
+		{"class", regexp.MustCompile(`^\s*(?:(?:public|protected|private|internal|static|abstract|final|sealed|data|open)\s+)*class\s+(\w+)`)},
+		{"interface", regexp.MustCompile(`^\s*(?:(?:public|protected|private|internal|static|sealed)\s+)*interface\s+(\w+)`)},
+		{"enum", regexp.MustCompile(`^\s*(?:(?:public|protected|private|internal|static)\s+)*enum\s+(?:class\s+)?(\w+)`)},
+		{"fun", regexp.MustCompile(`^\s*(?:(?:public|protected|private|internal|override|suspend|inline)\s+)*fun\s+(?:<[^>]*>\s*)?(?:\w+\.)?(\w+)`)},
+	}
+
+	symbolPatterns = map[string][]symbolPattern{
+		".py": pythonSymbols,
+		".js": scriptSymbols, ".jsx": scriptSymbols, ".mjs": scriptSymbols, ".cjs": scriptSymbols,
+		".ts": scriptSymbols, ".tsx": scriptSymbols,
+		".rs":   rustSymbols,
+		".rb":   rubySymbols,
+		".php":  phpSymbols,
+		".java": jvmSymbols, ".kt": jvmSymbols, ".cs": jvmSymbols, ".scala": jvmSymbols,
+	}
+
+	hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+)
+
+// ExtractSymbols lists the declarations in src. Go is parsed properly; other
+// languages are scanned line by line, so a symbol ends where the next begins.
+func ExtractSymbols(path string, src []byte) []Symbol {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".go" {
+		if symbols, err := goSymbols(src); err == nil {
+			return symbols
+		}
+	}
+
+	patterns, ok := symbolPatterns[ext]
+	if !ok {
+		return nil
+	}
+
+	var symbols []Symbol
+	lines := strings.Split(string(src), "\n")
+	for i, line := range lines {
+		for _, p := range patterns {
+			if m := p.pattern.FindStringSubmatch(line); m != nil {
+				if n := len(symbols); n > 0 {
+					symbols[n-1].End = i
+				}
+				symbols = append(symbols, Symbol{Kind: p.kind, Name: m[1], Start: i + 1, End: len(lines)})
+				break
+			}
+		}
+	}
+	return symbols
+}
+
+func goSymbols(src []byte) ([]Symbol, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []Symbol
+	span := func(node ast.Node, doc *ast.CommentGroup) (int, int) {
+		start := node.Pos()
+		if doc != nil {
+			start = doc.Pos()
+		}
+		return fset.Position(start).Line, fset.Position(node.End()).Line
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			start, end := span(d, d.Doc)
+			symbols = append(symbols, Symbol{Kind: "func", Name: goFuncName(d), Start: start, End: end})
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if len(d.Specs) == 1 {
+					doc = d.Doc
+				}
+				start, end := span(ts, doc)
+				symbols = append(symbols, Symbol{Kind: "type", Name: ts.Name.Name, Start: start, End: end})
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// goFuncName qualifies methods with their receiver type, e.g. "GitService.StageAll"
+func goFuncName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+
+	recv := d.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + d.Name.Name
+	}
+	return d.Name.Name
+}
+
+// DiffSymbols compares the declarations of two versions of a file. A symbol
+// counts as changed when a hunk of the zero-context diff touches its lines.
+func DiffSymbols(path string, oldSrc, newSrc []byte, diff string) SymbolChanges {
+	oldSymbols := ExtractSymbols(path, oldSrc)
+	newSymbols := ExtractSymbols(path, newSrc)
+	oldRanges, newRanges := hunkRanges(diff)
+
+	oldByName := map[string]Symbol{}
+	for _, s := range oldSymbols {
+		oldByName[s.String()] = s
+	}
+	newByName := map[string]Symbol{}
+	for _, s := range newSymbols {
+		newByName[s.String()] = s
+	}
+
+	var changes SymbolChanges
+	for _, s := range newSymbols {
+		old, existed := oldByName[s.String()]
+		switch {
+		case !existed:
+			changes.Added = append(changes.Added, s.String())
+		case touches(s, newRanges) || touches(old, oldRanges):
+			changes.Changed = append(changes.Changed, s.String())
+		}
+	}
+	for _, s := range oldSymbols {
+		if _, exists := newByName[s.String()]; !exists {
+			changes.Removed = append(changes.Removed, s.String())
+		}
+	}
+
+	changes.Added = uniqueSorted(changes.Added)
+	changes.Removed = uniqueSorted(changes.Removed)
+	changes.Changed = uniqueSorted(changes.Changed)
+	return changes
+}
+
+// hunkRanges returns the old and new line ranges of each hunk in a unified diff.
+// The empty side of a pure insertion or deletion has no range.
+func hunkRanges(diff string) ([][2]int, [][2]int) {
+	var oldRanges, newRanges [][2]int
+	for _, line := range strings.Split(diff, "\n") {
+		m := hunkHeaderPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if r, ok := lineRange(m[1], m[2]); ok {
+			oldRanges = append(oldRanges, r)
+		}
+		if r, ok := lineRange(m[3], m[4]); ok {
+			newRanges = append(newRanges, r)
+		}
+	}
+	return oldRanges, newRanges
+}
+
+func lineRange(startStr, countStr string) ([2]int, bool) {
+	start, _ := strconv.Atoi(startStr)
+	count := 1
+	if countStr != "" {
+		count, _ = strconv.Atoi(countStr)
+	}
+	return [2]int{start, start + count - 1}, count > 0
+}
+
+func touches(s Symbol, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if r[0] <= s.End && r[1] >= s.Start {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSorted(items []string) []string {
+	sort.Strings(items)
+	var out []string
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// maxListedSymbols keeps huge files from dominating the prompt
+const maxListedSymbols = 15
+
+// formatSymbolList joins symbol names, eliding the tail of long lists
+func formatSymbolList(symbols []string) string {
+	if len(symbols) <= maxListedSymbols {
+		return strings.Join(symbols, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(symbols[:maxListedSymbols], ", "), len(symbols)-maxListedSymbols)
+}
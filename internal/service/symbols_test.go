@@ -0,0 +1,86 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractSymbols_go(t *testing.T) {
+	src := `package svc
+
+// Store keeps values
+type Store[T any] struct{}
+
+func (s *Store[T]) Get(k string) T {
+	var zero T
+	return zero
+}
+
+func New() *Store[int] { return nil }
+`
+
+	var got []string
+	for _, s := range ExtractSymbols("svc.go", []byte(src)) {
+		got = append(got, s.String())
+	}
+	want := []string{"type Store", "func Store.Get", "func New"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExtractSymbols() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractSymbols_script(t *testing.T) {
+	src := `export interface Props {}
+export default function App() {}
+const useThing = async (x) => x
+class Store {}
+`
+
+	var got []string
+	for _, s := range ExtractSymbols("app.tsx", []byte(src)) {
+		got = append(got, s.String())
+	}
+	want := []string{"interface Props", "func App", "func useThing", "class Store"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExtractSymbols() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffSymbols(t *testing.T) {
+	oldSrc := `package svc
+
+func Old() int {
+	return 1
+}
+
+func Get(k string) string {
+	return k
+}
+
+func Keep() {}
+`
+	newSrc := `package svc
+
+func Get(k string) string {
+	return "v:" + k
+}
+
+func Keep() {}
+
+func New() {}
+`
+	diff := `@@ -3,4 +2,0 @@
+@@ -8 +4 @@
+@@ -11,0 +8,2 @@`
+
+	got := DiffSymbols("svc.go", []byte(oldSrc), []byte(newSrc), diff)
+	if !slices.Equal(got.Added, []string{"func New"}) {
+		t.Fatalf("Added = %v", got.Added)
+	}
+	if !slices.Equal(got.Removed, []string{"func Old"}) {
+		t.Fatalf("Removed = %v", got.Removed)
+	}
+	if !slices.Equal(got.Changed, []string{"func Get"}) {
+		t.Fatalf("Changed = %v, want only Get", got.Changed)
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+// attachContext appends the contents of files and the output of commands to the
+// user context, so the edit-context form and the prompt both see all of it
+func attachContext(userContext *string, files []string, commands []string, quiet bool) error {
+	if len(files) == 0 && len(commands) == 0 {
+		return nil
+	}
+
+	var attachments []string
+	for _, file := range files {
+		text, err := service.ContextFile(file)
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, text)
+	}
+	for _, command := range commands {
+		if !quiet {
+			color.New(color.FgCyan).Printf("Running context command: %s\n", command)
+		}
+		text, err := service.ContextCommand(command)
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, text)
+	}
+
+	*userContext = service.CombineContext(*userContext, attachments)
+	return nil
+}
+
+// attachIssueDetails adds the title and description of each issue to the user
+// context. A tracker that is down or slow must not block the commit, so
+// failures are only reported.
+func attachIssueDetails(
+	ctx context.Context,
+	issueConfig *service.IssueConfig,
+	issues []service.IssueRef,
+	userContext *string,
+	quiet bool,
+) {
+	if issueConfig == nil || !issueConfig.Fetch || len(issues) == 0 {
+		return
+	}
+
+	client := service.NewIssueHTTPClient()
+	var attachments []string
+	for _, issue := range issues {
+		details, err := issueConfig.FetchIssue(ctx, client, issue.ID)
+		if err != nil {
+			if !quiet {
+				color.New(color.FgYellow).Printf("Skipping issue details: %v\n", err)
+			}
+			continue
+		}
+		attachments = append(attachments, details.String())
+	}
+
+	*userContext = service.CombineContext(*userContext, attachments)
+}
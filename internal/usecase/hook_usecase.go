@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+type HookUsecase struct {
+	gitService *service.GitService
+}
+
+func NewHookUsecase() *HookUsecase {
+	return &HookUsecase{gitService: service.NewGitService()}
+}
+
+func (h *HookUsecase) InstallCommand(hook string, fix bool) error {
+	if err := validHook(hook); err != nil {
+		return err
+	}
+	dir, err := h.hooksDir()
+	if err != nil {
+		return err
+	}
+
+	// The hook calls this very binary, so it works without gmc on the IDE's PATH
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the gmc executable: %v", err)
+	}
+
+	var flags []string
+	if fix {
+		if hook != service.CommitMsgHook {
+			return fmt.Errorf("--fix only applies to the commit-msg hook")
+		}
+		flags = append(flags, "--fix")
+	}
+
+	backup, err := service.InstallHook(dir, hook, service.HookScript(hook, executable, flags...))
+	if err != nil {
+		return err
+	}
+	if backup != "" {
+		color.New(color.FgYellow).Printf("Kept the existing hook as %s, it runs first\n", backup)
+	}
+	color.New(color.FgGreen).Printf("Installed %s hook in %s\n", hook, dir)
+	return nil
+}
+
+func (h *HookUsecase) UninstallCommand(hook string) error {
+	if err := validHook(hook); err != nil {
+		return err
+	}
+	dir, err := h.hooksDir()
+	if err != nil {
+		return err
+	}
+
+	restored, err := service.UninstallHook(dir, hook)
+	if err != nil {
+		return err
+	}
+	if restored {
+		color.New(color.FgCyan).Printf("Restored the previous %s hook\n", hook)
+	}
+	color.New(color.FgGreen).Printf("Removed %s hook from %s\n", hook, dir)
+	return nil
+}
+
+func (h *HookUsecase) hooksDir() (string, error) {
+	if err := h.gitService.VerifyGitInstallation(); err != nil {
+		return "", err
+	}
+	if err := h.gitService.VerifyGitRepository(); err != nil {
+		return "", err
+	}
+	dir, err := h.gitService.HooksDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(dir), nil
+}
+
+func validHook(hook string) error {
+	if hook != service.PrepareCommitMsgHook && hook != service.CommitMsgHook {
+		return fmt.Errorf("unsupported hook %q, expected %s or %s", hook, service.PrepareCommitMsgHook, service.CommitMsgHook)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"google.golang.org/genai"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+type LintUsecase struct {
+	gitService         *service.GitService
+	geminiService      *service.GeminiService
+	interactionService *service.InteractionService
+}
+
+func NewLintUsecase() *LintUsecase {
+	return &LintUsecase{
+		gitService:         service.NewGitService(),
+		geminiService:      service.NewGeminiService(),
+		interactionService: service.NewInteractionService(),
+	}
+}
+
+func (l *LintUsecase) LintCommand(
+	ctx context.Context,
+	apiKey string,
+	file string,
+	revisionRange *string,
+	stdin *bool,
+	aiSuggest *bool,
+	fix *bool,
+	model *string,
+	subjectMaxLength *int,
+	bodyWrap *int,
+	language *string,
+	customBaseUrl *string,
+) error {
+	if *fix && file == "" {
+		return fmt.Errorf("--fix rewrites a message file, it can't be used with --range or --stdin")
+	}
+
+	messages, err := l.collectMessages(file, *revisionRange, *stdin)
+	if err != nil {
+		return err
+	}
+
+	// Outside a repository there is no commitlint config, only the defaults apply
+	root := ""
+	if l.gitService.VerifyGitRepository() == nil {
+		root, _ = l.gitService.GetRepoRoot()
+	}
+	lintRules, err := service.LoadLintRules(root, *subjectMaxLength, *bodyWrap)
+	if err != nil {
+		color.New(color.FgYellow).Printf("Ignoring commitlint config: %v\n", err)
+	}
+
+	var client *genai.Client
+	if *aiSuggest || *fix {
+		client, err = service.NewGeminiClient(ctx, apiKey, customBaseUrl)
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, m := range messages {
+		if service.IsIgnoredCommitMessage(m.Message) {
+			continue
+		}
+
+		violations := service.LintCommitMessage(m.Message, lintRules)
+		l.interactionService.DisplayLintResult(m.Hash, m.Message, violations)
+		// Warnings are shown but don't fail the message
+		violations = service.LintErrors(violations)
+		if len(violations) == 0 {
+			continue
+		}
+		failed++
+
+		if *fix {
+			if l.fixMessageFile(client, ctx, file, m.Message, model, language, lintRules) {
+				failed--
+			}
+			continue
+		}
+
+		if client != nil {
+			suggestion, err := l.geminiService.RepairCommitMessage(
+				client,
+				ctx,
+				m.Message,
+				violations,
+				model,
+				language,
+				lintRules,
+			)
+			if err != nil {
+				color.New(color.FgYellow).Printf("    Could not get a suggestion: %v\n", err)
+				continue
+			}
+			l.interactionService.DisplaySuggestedMessage(suggestion)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d commit messages failed linting", failed, len(messages))
+	}
+
+	return nil
+}
+
+// fixMessageFile rewrites the message file with a corrected message, keeping
+// the author's intent. The file is only touched when the correction passes.
+func (l *LintUsecase) fixMessageFile(
+	client *genai.Client,
+	ctx context.Context,
+	file string,
+	message string,
+	model *string,
+	language *string,
+	lintRules *service.LintRules,
+) bool {
+	fixed := l.geminiService.EnforceLintRules(client, ctx, message, model, language, lintRules, nil)
+	if violations := service.LintErrors(service.LintCommitMessage(fixed, lintRules)); len(violations) > 0 {
+		color.New(color.FgYellow).Println("    Could not fix the message automatically")
+		return false
+	}
+
+	if err := os.WriteFile(file, []byte(fixed+"\n"), 0o644); err != nil {
+		color.New(color.FgYellow).Printf("    Could not write the fixed message: %v\n", err)
+		return false
+	}
+	l.interactionService.DisplayLintResult(filepath.Base(file)+" (fixed)", fixed, service.LintCommitMessage(fixed, lintRules))
+	return true
+}
+
+// collectMessages reads the messages to lint; Hash holds a display name for non-commits
+func (l *LintUsecase) collectMessages(
+	file string,
+	revisionRange string,
+	stdin bool,
+) ([]service.CommitMessage, error) {
+	switch {
+	case revisionRange != "":
+		if err := l.gitService.VerifyGitRepository(); err != nil {
+			return nil, err
+		}
+		commits, err := l.gitService.GetCommitMessages(revisionRange)
+		if err != nil {
+			return nil, err
+		}
+		for i := range commits {
+			commits[i].Hash = commits[i].Hash[:min(len(commits[i].Hash), 12)]
+		}
+		return commits, nil
+	case stdin:
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %v", err)
+		}
+		return []service.CommitMessage{
+			{Hash: "stdin", Message: service.CleanCommitMessage(string(content))},
+		}, nil
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit message file: %v", err)
+		}
+		return []service.CommitMessage{
+			{Hash: filepath.Base(file), Message: service.CleanCommitMessage(string(content))},
+		}, nil
+	default:
+		return nil, fmt.Errorf("nothing to lint. pass a message file, --range or --stdin")
+	}
+}
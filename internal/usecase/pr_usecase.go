@@ -38,9 +38,33 @@ func (p *PRUsecase) PRCommand(
 	maxLength *int,
 	language *string,
 	userContext *string,
+	contextFiles *[]string,
+	contextCmds *[]string,
 	draft *bool,
 	customBaseUrl *string,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
 ) error {
+	if !service.ValidPrivacyMode(*privacy) {
+		return fmt.Errorf("invalid privacy mode %q, expected full, symbols or stats", *privacy)
+	}
+	diffOpts := &service.DiffOptions{
+		Algorithm:        *diffAlgorithm,
+		ContextLines:     *diffContext,
+		FunctionContext:  *diffFuncContext,
+		IgnoreWhitespace: *diffIgnoreSpace,
+		Renames:          *diffRenames,
+	}
+	if err := diffOpts.Validate(); err != nil {
+		return err
+	}
+
 	client, err := service.NewGeminiClient(ctx, apiKey, customBaseUrl)
 	if err != nil {
 		fmt.Printf("Error getting gemini client: %v", err)
@@ -66,11 +90,19 @@ func (p *PRUsecase) PRCommand(
 		UserContext: userContext,
 	}
 
-	data, err := p.gitService.GetDiff()
+	if err := attachContext(opts.UserContext, *contextFiles, *contextCmds, *opts.Quiet); err != nil {
+		return err
+	}
+
+	data, err := p.gitService.GetDiff(diffOpts, *privacy)
 	if err != nil {
 		return err
 	}
 
+	if err := redactSecrets(p.interactionService, data, opts.UserContext, *secretAction, *secretPatterns, *opts.Quiet, *opts.NoConfirm); err != nil {
+		return err
+	}
+
 	if *opts.ShowDiff && !*opts.Quiet {
 		p.interactionService.DisplayDiff(data.Diff)
 	}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"google.golang.org/genai"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+type RewordUsecase struct {
+	gitService         *service.GitService
+	geminiService      *service.GeminiService
+	interactionService *service.InteractionService
+}
+
+func NewRewordUsecase() *RewordUsecase {
+	return &RewordUsecase{
+		gitService:         service.NewGitService(),
+		geminiService:      service.NewGeminiService(),
+		interactionService: service.NewInteractionService(),
+	}
+}
+
+func (r *RewordUsecase) RewordCommand(
+	ctx context.Context,
+	apiKey string,
+	revisionRange string,
+	userContext *string,
+	model *string,
+	noConfirm *bool,
+	dryRun *bool,
+	force *bool,
+	subjectMaxLength *int,
+	bodyWrap *int,
+	language *string,
+	customBaseUrl *string,
+	diffExclude *[]string,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	diffFullFilesKB *int,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
+) error {
+	if !service.ValidPrivacyMode(*privacy) {
+		return fmt.Errorf("invalid privacy mode %q, expected full, symbols or stats", *privacy)
+	}
+	diffOpts := &service.DiffOptions{
+		Algorithm:        *diffAlgorithm,
+		ContextLines:     *diffContext,
+		FunctionContext:  *diffFuncContext,
+		IgnoreWhitespace: *diffIgnoreSpace,
+		Renames:          *diffRenames,
+		FullFilesKB:      *diffFullFilesKB,
+	}
+	if err := diffOpts.Validate(); err != nil {
+		return err
+	}
+	// Each commit is redacted separately, so a bad pattern is caught up front
+	if *secretAction != service.SecretActionOff {
+		if _, err := service.SecretRules(*secretPatterns); err != nil {
+			return err
+		}
+	}
+
+	if err := r.gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := r.gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+
+	commits, err := r.gitService.GetCommitMessages(revisionRange)
+	if err != nil {
+		return err
+	}
+	if err := r.gitService.CheckRewordRange(revisionRange, commits); err != nil {
+		return err
+	}
+	pushed, err := r.gitService.HasPushedCommits(revisionRange, commits)
+	if err != nil {
+		return err
+	}
+	if pushed && !*force {
+		return fmt.Errorf("some of these commits have already been pushed, rewording them rewrites published history. use --force to reword anyway")
+	}
+
+	client, err := service.NewGeminiClient(ctx, apiKey, customBaseUrl)
+	if err != nil {
+		return err
+	}
+
+	root, _ := r.gitService.GetRepoRoot()
+	lintRules, err := service.LoadLintRules(root, *subjectMaxLength, *bodyWrap)
+	if err != nil {
+		color.New(color.FgYellow).Printf("Ignoring commitlint config: %v\n", err)
+	}
+	diffOpts.Exclude = service.LoadDiffExcludes(root, *diffExclude)
+
+	// Generate everything first, so the review isn't interrupted by spinners
+	generated := make(map[string]string, len(commits))
+	for _, c := range commits {
+		// Autosquash relies on the fixup!/squash! subjects
+		if service.IsIgnoredCommitMessage(c.Message) {
+			continue
+		}
+		message, err := r.generateMessage(client, ctx, c, diffOpts, *privacy, lintRules, *userContext, model, language, subjectMaxLength, *secretAction, *secretPatterns, *noConfirm)
+		if err != nil {
+			color.New(color.FgYellow).Printf("Keeping the message of %s: %v\n", c.Hash[:12], err)
+			continue
+		}
+		generated[c.Hash] = message
+	}
+
+	messages := make(map[string]string, len(generated))
+	for _, c := range commits {
+		message, ok := generated[c.Hash]
+		if !ok || message == c.Message {
+			continue
+		}
+		if *noConfirm {
+			messages[c.Hash] = message
+			continue
+		}
+
+		action, message, err := r.interactionService.ReviewRewordedMessage(c.Hash[:12], c.Message, message)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case service.ActionConfirm:
+			messages[c.Hash] = message
+		case service.ActionCancel:
+			color.New(color.FgRed).Println("Reword cancelled")
+			return nil
+		}
+	}
+
+	if len(messages) == 0 {
+		color.New(color.FgCyan).Println("No commit messages to change")
+		return nil
+	}
+	if *dryRun {
+		color.New(color.FgYellow).Println("🔍 DRY RUN - No changes will be made")
+		color.New(color.FgCyan).Printf("Would reword %d of %d commits\n", len(messages), len(commits))
+		return nil
+	}
+
+	tip, err := r.gitService.RewordCommits(commits, messages)
+	if err != nil {
+		return err
+	}
+	color.New(color.FgGreen).Printf("✔ Reworded %d of %d commits, HEAD is now %s\n", len(messages), len(commits), tip[:12])
+	color.New(color.FgCyan).Printf("The previous commits are kept in %s; undo with: git reset --soft %s\n", service.RewordBackupRef, service.RewordBackupRef)
+	return nil
+}
+
+// generateMessage writes a new message for c from its own diff. The old
+// message is passed along for intent and its trailers are kept.
+func (r *RewordUsecase) generateMessage(
+	client *genai.Client,
+	ctx context.Context,
+	c service.CommitMessage,
+	diffOpts *service.DiffOptions,
+	privacy string,
+	lintRules *service.LintRules,
+	userContext string,
+	model *string,
+	language *string,
+	subjectMaxLength *int,
+	secretAction string,
+	secretPatterns []string,
+	noConfirm bool,
+) (string, error) {
+	data, err := r.gitService.PrepareCommitData(c.Hash, diffOpts, privacy)
+	if err != nil {
+		return "", err
+	}
+
+	commitContext := service.CombineContext(userContext, []string{"The commit's current message, which may be a placeholder:\n" + c.Message})
+	if err := redactSecrets(r.interactionService, data, &commitContext, secretAction, secretPatterns, false, noConfirm); err != nil {
+		return "", err
+	}
+
+	quiet := false
+	opts := &service.CommitOptions{
+		UserContext: &commitContext,
+		Model:       model,
+		Quiet:       &quiet,
+		MaxLength:   subjectMaxLength,
+		Language:    language,
+		LintRules:   lintRules,
+		// The old message's trailers are kept
+		Finish: func(message string) string {
+			return service.AppendTrailers(message, service.MessageTrailers(c.Message))
+		},
+	}
+	return r.geminiService.GenerateCommitMessage(client, ctx, data, opts)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/fatih/color"
@@ -32,19 +33,93 @@ func (r *RootUsecase) RootCommand(
 	stageAll *bool,
 	autoSelect *bool,
 	userContext *string,
+	contextFiles *[]string,
+	contextCmds *[]string,
 	model *string,
 	noConfirm *bool,
 	quiet *bool,
 	push *bool,
 	dryRun *bool,
 	showDiff *bool,
-	maxLength *int,
+	subjectMaxLength *int,
+	bodyWrap *int,
 	language *string,
-	issue *string,
+	commitType *string,
+	commitScope *string,
+	breaking *bool,
+	issues *[]string,
 	issueFooter *string,
+	issuePatterns *[]string,
+	issueTracker *string,
+	issueURL *string,
+	issuePlacement *string,
+	issueFetch *bool,
+	issueRepo *string,
+	issueToken *string,
+	issueAPIURL *string,
+	signoff *bool,
+	coAuthors *[]string,
+	coAuthorAliases *map[string]string,
+	commitTrailers *[]string,
 	noVerify *bool,
 	customBaseUrl *string,
+	scopeMap *map[string]string,
+	branchTypes *map[string]string,
+	enforceBranchType *bool,
+	diffExclude *[]string,
+	diffMaxFileKB *int,
+	diffAlgorithm *string,
+	diffContext *int,
+	diffFuncContext *bool,
+	diffIgnoreSpace *bool,
+	diffRenames *string,
+	diffFullFilesKB *int,
+	secretAction *string,
+	secretPatterns *[]string,
+	privacy *string,
+	neighbors *string,
+	messageFile *string,
+	amend *bool,
+	force *bool,
 ) error {
+	if !service.ValidPrivacyMode(*privacy) {
+		return fmt.Errorf("invalid privacy mode %q, expected full, symbols or stats", *privacy)
+	}
+	issueConfig := &service.IssueConfig{
+		Patterns:  *issuePatterns,
+		Tracker:   *issueTracker,
+		URL:       *issueURL,
+		Placement: *issuePlacement,
+		Fetch:     *issueFetch,
+		Repo:      *issueRepo,
+		Token:     *issueToken,
+		APIURL:    *issueAPIURL,
+	}
+	if err := issueConfig.Validate(); err != nil {
+		return err
+	}
+	override := service.HeaderOverride{Type: *commitType, Scope: *commitScope, Breaking: *breaking}
+	if err := override.Validate(); err != nil {
+		return err
+	}
+	if !service.ValidNeighborsMode(*neighbors) {
+		return fmt.Errorf("invalid context.neighbors %q, expected off, tracked or all", *neighbors)
+	}
+
+	// Keep lockfiles, snapshots and generated code from swamping the prompt
+	diffOpts := &service.DiffOptions{
+		MaxFileKB:        *diffMaxFileKB,
+		Algorithm:        *diffAlgorithm,
+		ContextLines:     *diffContext,
+		FunctionContext:  *diffFuncContext,
+		IgnoreWhitespace: *diffIgnoreSpace,
+		Renames:          *diffRenames,
+		FullFilesKB:      *diffFullFilesKB,
+	}
+	if err := diffOpts.Validate(); err != nil {
+		return err
+	}
+
 	client, err := service.NewGeminiClient(ctx, apiKey, customBaseUrl)
 	if err != nil {
 		fmt.Printf("Error getting gemini client: %v", err)
@@ -71,30 +146,131 @@ func (r *RootUsecase) RootCommand(
 		Push:        push,
 		DryRun:      dryRun,
 		ShowDiff:    showDiff,
-		MaxLength:   maxLength,
+		MaxLength:   subjectMaxLength,
 		Language:    language,
-		Issue:       issue,
+		Issues:      issues,
 		NoVerify:    noVerify,
+		ScopeMap:    scopeMap,
+		BranchTypes: branchTypes,
+		Privacy:     privacy,
+		Neighbors:   neighbors,
+		IssueConfig: issueConfig,
+	}
+
+	// Trailers go after the issue footer, with the sign-off last like `git commit -s`
+	trailers := append([]string{}, *commitTrailers...)
+	coAuthorTrailers, err := service.CoAuthorTrailers(*coAuthors, *coAuthorAliases)
+	if err != nil {
+		return err
+	}
+	trailers = append(trailers, coAuthorTrailers...)
+	if *signoff {
+		signoffTrailer, err := r.gitService.SignoffTrailer()
+		if err != nil {
+			return err
+		}
+		trailers = append(trailers, signoffTrailer)
+	}
+
+	if *amend {
+		if err := r.prepareAmend(&trailers, *force); err != nil {
+			return err
+		}
 	}
 
+	if err := attachContext(opts.UserContext, *contextFiles, *contextCmds, *opts.Quiet); err != nil {
+		return err
+	}
+
+	// Validate against geminicommit's own rules, tightened by the repository's
+	// commitlint config so CI accepts the generated message
+	root, _ := r.gitService.GetRepoRoot()
+	lintRules, err := service.LoadLintRules(root, *subjectMaxLength, *bodyWrap)
+	if err != nil && !*opts.Quiet {
+		color.New(color.FgYellow).Printf("Ignoring commitlint config: %v\n", err)
+	}
+	if lintRules.Source != "" && !*opts.Quiet {
+		color.New(color.FgCyan).Printf("Using commitlint rules from %s\n", lintRules.Source)
+	}
+	opts.LintRules = lintRules
+
+	diffOpts.Exclude = service.LoadDiffExcludes(root, *diffExclude)
+	opts.Diff = diffOpts
+
 	// Detect and prepare changes
 	data, err := r.gitService.DetectAndPrepareChanges(opts)
 	if err != nil {
 		return err
 	}
 
+	if len(data.Scopes) > 0 {
+		opts.LintRules = opts.LintRules.WithScopes(data.Scopes)
+		if !*opts.Quiet {
+			color.New(color.FgCyan).Printf("Scope from changed paths: %s\n", strings.Join(data.Scopes, ", "))
+		}
+	}
+
+	// A fix branch shouldn't end up with a feat commit and the wrong release bump
+	if data.BranchType != "" && override.Type == "" {
+		opts.LintRules = opts.LintRules.WithBranchType(data.BranchType, *enforceBranchType)
+		if !*opts.Quiet && opts.LintRules.PreferredType == data.BranchType {
+			how := "suggested"
+			if *enforceBranchType {
+				how = "enforced"
+			}
+			color.New(color.FgCyan).Printf("Type from branch name: %s (%s)\n", data.BranchType, how)
+		}
+	}
+	opts.LintRules = opts.LintRules.WithOverride(override)
+
 	// Display detected files (skip this in auto mode since AI will select a subset later)
 	if !*opts.AutoSelect {
 		r.interactionService.DisplayDetectedFiles(data.Files, opts.Quiet)
 	}
 
+	// The ticket usually explains why the change was made
+	if opts.IssueConfig.Fetch && opts.IssueConfig.Repo == "" {
+		opts.IssueConfig.Repo, _ = r.gitService.RemoteURL("origin")
+	}
+	attachIssueDetails(ctx, opts.IssueConfig, data.Issues, opts.UserContext, *opts.Quiet)
+
+	// Nothing that looks like a credential may reach the API
+	if err := redactSecrets(r.interactionService, data, opts.UserContext, *secretAction, *secretPatterns, *opts.Quiet, *opts.NoConfirm); err != nil {
+		return err
+	}
+
 	// Show diff if requested
 	if *opts.ShowDiff && !*opts.Quiet {
 		r.interactionService.DisplayDiff(data.Diff)
 	}
 
+	// Every message, generated or not, gets the fixed header parts, the issue
+	// reference and the trailers
+	finishMessage := func(message string) string {
+		// The model is asked for the fixed type and scope, but not trusted with them
+		message = override.Apply(message)
+		message = opts.IssueConfig.AppendIssueRefs(message, data.Issues, *issueFooter)
+		return service.AppendTrailers(message, trailers)
+	}
+	// Generated messages are finished before they are linted
+	opts.Finish = finishMessage
+
 	// Check if auto-select flag is set and handle accordingly
 	var initialCommitMessage string
+
+	// Dependency bumps follow a fixed pattern, so there's no need to ask the model
+	if !*opts.AutoSelect && data.OnlyDependencies {
+		if message := service.DependencyCommitMessage(data.Dependencies, *opts.MaxLength); message != "" {
+			message = finishMessage(message)
+			if len(service.LintErrors(service.LintCommitMessage(message, opts.LintRules))) == 0 {
+				initialCommitMessage = message
+			}
+		}
+		if initialCommitMessage != "" && !*opts.Quiet {
+			color.New(color.FgCyan).Println("Only dependencies changed, message written without calling Gemini")
+		}
+	}
+
 	if *opts.AutoSelect {
 		// Auto flow: Select files with AI and generate commit message in one request
 		autoResult, err := r.handleAutoFlow(client, ctx, data, opts)
@@ -103,7 +279,6 @@ func (r *RootUsecase) RootCommand(
 		}
 		data = autoResult.Data // Update data with confirmed files
 		initialCommitMessage = autoResult.CommitMessage
-		initialCommitMessage = service.AppendIssueFooter(initialCommitMessage, data.Issue, *issueFooter)
 
 		// In auto mode, we need to stage only the selected files for the commit
 		// First, unstage everything
@@ -117,6 +292,19 @@ func (r *RootUsecase) RootCommand(
 		}
 	}
 
+	// Under prepare-commit-msg git opens the editor itself, so the message only
+	// goes into its message file
+	if *messageFile != "" {
+		if initialCommitMessage == "" {
+			message, err := r.geminiService.GenerateCommitMessage(client, ctx, data, opts)
+			if err != nil {
+				return err
+			}
+			initialCommitMessage = message
+		}
+		return service.PrependMessageFile(*messageFile, initialCommitMessage)
+	}
+
 	// Main generation loop
 	message := initialCommitMessage
 	for {
@@ -127,9 +315,13 @@ func (r *RootUsecase) RootCommand(
 			if err != nil {
 				return err
 			}
-			message = service.AppendIssueFooter(message, data.Issue, *issueFooter)
 		}
 
+		r.interactionService.DisplayLintViolations(
+			service.LintCommitMessage(message, opts.LintRules),
+			opts.Quiet,
+		)
+
 		selectedAction, finalMessage, err := r.interactionService.HandleUserAction(message, opts)
 		if err != nil {
 			return err
@@ -137,6 +329,9 @@ func (r *RootUsecase) RootCommand(
 
 		switch selectedAction {
 		case service.ActionConfirm:
+			if *amend {
+				return r.gitService.AmendCommit(finalMessage, opts.Quiet, opts.DryRun, opts.NoVerify)
+			}
 			if err := r.gitService.ConfirmAction(finalMessage, opts.Quiet, opts.Push, opts.DryRun, opts.NoVerify); err != nil {
 				return err
 			}
@@ -154,6 +349,31 @@ func (r *RootUsecase) RootCommand(
 	}
 }
 
+// prepareAmend makes the changes compare with HEAD's parent, so the new
+// message describes the whole amended commit, and keeps HEAD's trailers.
+// Rewriting a pushed commit needs force.
+func (r *RootUsecase) prepareAmend(trailers *[]string, force bool) error {
+	base, err := r.gitService.AmendBase()
+	if err != nil {
+		return err
+	}
+	pushed, err := r.gitService.IsHeadPushed()
+	if err != nil {
+		return err
+	}
+	if pushed && !force {
+		return fmt.Errorf("the last commit has already been pushed, amending it rewrites published history. use --force to amend anyway")
+	}
+	r.gitService.CompareStagedWith(base)
+
+	message, err := r.gitService.HeadMessage()
+	if err != nil {
+		return err
+	}
+	*trailers = append(service.MessageTrailers(message), *trailers...)
+	return nil
+}
+
 // AutoFlowResult contains both selected files and generated commit message
 type AutoFlowResult struct {
 	Data          *service.PreCommitData
@@ -177,12 +397,13 @@ func (r *RootUsecase) handleAutoFlow(
 			ModelName:    opts.Model,
 			MaxLength:    opts.MaxLength,
 			Language:     opts.Language,
-			Issue:        &data.Issue,
+			Issues:       data.Issues,
+			LintRules:    opts.LintRules,
 		}
 		selectedFiles, commitMessage, err := r.geminiService.SelectFilesAndGenerateCommit(
 			client,
 			ctx,
-			data.Diff,
+			data.PromptDiff(),
 			selectOpts,
 		)
 		if err != nil {
@@ -191,6 +412,15 @@ func (r *RootUsecase) handleAutoFlow(
 		if commitMessage == "" {
 			return nil, "", fmt.Errorf("AI returned an empty commit message")
 		}
+		commitMessage = r.geminiService.EnforceLintRules(
+			client,
+			ctx,
+			commitMessage,
+			opts.Model,
+			opts.Language,
+			opts.LintRules,
+			opts.Finish,
+		)
 		return selectedFiles, commitMessage, nil
 	}
 
@@ -232,18 +462,14 @@ func (r *RootUsecase) handleAutoFlow(
 			return nil, err
 		}
 		// Update data with edited files
-		newData := *data
-		newData.Files = editedFiles
 		return &AutoFlowResult{
-			Data:          &newData,
+			Data:          r.gitService.SelectFiles(data, editedFiles),
 			CommitMessage: commitMessage,
 		}, nil
 	case service.ActionConfirm, service.ActionAutoSelect:
 		// Proceed with selected files
-		newData := *data
-		newData.Files = confirmedFiles
 		return &AutoFlowResult{
-			Data:          &newData,
+			Data:          r.gitService.SelectFiles(data, confirmedFiles),
 			CommitMessage: commitMessage,
 		}, nil
 	default:
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/tfkhdyt/geminicommit/internal/service"
+)
+
+// redactSecrets masks likely secrets in the diff and the user context before they
+// are sent to Gemini, then warns about those in the diff or aborts depending on
+// action. An invalid custom pattern stops everything, since the secrets it was
+// meant to catch would otherwise be sent.
+func redactSecrets(
+	interactionService *service.InteractionService,
+	data *service.PreCommitData,
+	userContext *string,
+	action string,
+	patterns []string,
+	quiet bool,
+	noConfirm bool,
+) error {
+	if action == service.SecretActionOff {
+		return nil
+	}
+
+	rules, err := service.SecretRules(patterns)
+	if err != nil {
+		return err
+	}
+
+	var findings []service.SecretFinding
+	data.Diff, findings = service.RedactSecrets(data.Diff, rules)
+	service.RedactFileContents(data.FileContents, rules)
+	if userContext != nil {
+		*userContext = service.RedactText(*userContext, rules)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	if action == service.SecretActionAbort {
+		interactionService.DisplaySecretFindings(findings)
+		return fmt.Errorf("aborted: %d possible secret(s) found, remove them or set secrets.action to warn", len(findings))
+	}
+
+	if quiet {
+		return nil
+	}
+	interactionService.DisplaySecretFindings(findings)
+	if noConfirm {
+		return nil
+	}
+
+	proceed, err := interactionService.ConfirmSecrets()
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return fmt.Errorf("aborted: possible secrets in the changes")
+	}
+	return nil
+}